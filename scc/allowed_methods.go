@@ -0,0 +1,109 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ReadOnlyHTTPMethods restricts a resource to reading.
+var ReadOnlyHTTPMethods = []string{http.MethodGet, http.MethodHead}
+
+type resourceAllowedMethods struct {
+	AllowedMethods []string `json:"allowedMethods"`
+}
+
+// GetResourceAllowedMethods gets the HTTP methods allowed on a resource of an
+// HTTP system mapping. An empty list means that all methods are allowed. It
+// returns ErrNotSupported if the connector cannot restrict methods.
+func (s *SystemMappingService) GetResourceAllowedMethods(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string) ([]string, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources/%v/allowedMethods", regionHost, subaccount, virtualHost, virtualPort, encodeResourceID(resourceID))
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	methods := new(resourceAllowedMethods)
+	resp, err := s.client.Do(ctx, req, methods)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if methods.AllowedMethods == nil {
+		methods.AllowedMethods = []string{}
+	}
+	return methods.AllowedMethods, resp, nil
+}
+
+// SetResourceAllowedMethods restricts a resource of an HTTP system mapping to
+// the given HTTP methods, e.g. ReadOnlyHTTPMethods, and returns the effective
+// set. Method names are case-insensitive; an empty list allows all methods. It
+// returns ErrNotSupported if the connector cannot restrict methods.
+func (s *SystemMappingService) SetResourceAllowedMethods(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string, methods []string) ([]string, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+	normalized, err := normalizeHTTPMethods(methods)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources/%v/allowedMethods", regionHost, subaccount, virtualHost, virtualPort, encodeResourceID(resourceID))
+	req, err := s.client.NewRequest("PUT", u, &resourceAllowedMethods{AllowedMethods: normalized})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(resourceAllowedMethods)
+	resp, err := s.client.Do(ctx, req, updated)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	// Setters may answer without a body, in which case the request was
+	// applied as sent.
+	if updated.AllowedMethods == nil {
+		updated.AllowedMethods = normalized
+	}
+	return updated.AllowedMethods, resp, nil
+}
+
+// normalizeHTTPMethods upper-cases methods and removes duplicates. It returns
+// an error for methods that are not HTTP methods.
+func normalizeHTTPMethods(methods []string) ([]string, error) {
+	normalized := make([]string, 0, len(methods))
+	seen := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if !isHTTPMethod(m) {
+			return nil, fmt.Errorf("invalid HTTP method %q", m)
+		}
+		if !seen[m] {
+			seen[m] = true
+			normalized = append(normalized, m)
+		}
+	}
+	return normalized, nil
+}
+
+// isHTTPMethod reports whether m is an HTTP method access to a resource can be
+// restricted to.
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodTrace, http.MethodConnect:
+		return true
+	}
+	return false
+}
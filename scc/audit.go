@@ -0,0 +1,199 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Audit levels that can be configured for a subaccount.
+const (
+	AuditLevelSecurity = "SECURITY"
+	AuditLevelAll      = "ALL"
+	AuditLevelOff      = "OFF"
+)
+
+type AuditLevel struct {
+	AuditLevel string `json:"auditLevel"`
+}
+
+// AuditLevelReport is the result of reading the audit level of every
+// subaccount.
+type AuditLevelReport struct {
+	// Levels holds the audit level of each subaccount that could be read.
+	Levels map[SubaccountKey]string
+
+	// Deviating lists the subaccounts whose audit level differs from the
+	// expected one, sorted by region host and subaccount.
+	Deviating []SubaccountKey
+
+	// Errors holds the error for each subaccount whose audit level could not
+	// be read.
+	Errors map[SubaccountKey]error
+}
+
+// GetAuditLevel gets the audit level of a subaccount
+func (s *SubaccountService) GetAuditLevel(ctx context.Context, regionHost, subaccount string) (*AuditLevel, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/auditLogs", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	auditLevel := new(AuditLevel)
+	resp, err := s.client.Do(ctx, req, auditLevel)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return auditLevel, resp, nil
+}
+
+// SetAuditLevel sets the audit level of a subaccount
+func (s *SubaccountService) SetAuditLevel(ctx context.Context, regionHost, subaccount, level string) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+	if err := validateAuditLevel(level); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/auditLogs", regionHost, subaccount)
+	req, err := s.client.NewRequest("PUT", u, &AuditLevel{AuditLevel: level})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ListAuditLevels reads the audit level of all subaccounts concurrently. Any
+// subaccount whose level differs from expected is reported as deviating; pass
+// an empty expected level to skip that check. Failures for individual
+// subaccounts do not abort the listing: they are collected in the report and
+// also returned, together with the report, as a *MultiError keyed by
+// subaccount.
+func (s *SubaccountService) ListAuditLevels(ctx context.Context, expected string) (*AuditLevelReport, error) {
+	keys, err := s.listSubaccountKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AuditLevelReport{
+		Levels: make(map[SubaccountKey]string),
+		Errors: make(map[SubaccountKey]error),
+	}
+	var mu sync.Mutex
+	forEachSubaccount(keys, defaultConcurrency, func(k SubaccountKey) {
+		level, _, err := s.GetAuditLevel(ctx, k.RegionHost, k.Subaccount)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Errors[k] = err
+			return
+		}
+		report.Levels[k] = level.AuditLevel
+		if expected != "" && level.AuditLevel != expected {
+			report.Deviating = append(report.Deviating, k)
+		}
+	})
+	sort.Slice(report.Deviating, func(i, j int) bool {
+		return report.Deviating[i].String() < report.Deviating[j].String()
+	})
+
+	return report, subaccountErrors(report.Errors)
+}
+
+// SetAuditLevelAll sets the audit level of every subaccount, updating several
+// subaccounts concurrently. It continues when individual updates fail and
+// returns the outcome for each subaccount, a nil error meaning success. If any
+// update failed, the error is a *MultiError keyed by subaccount.
+func (s *SubaccountService) SetAuditLevelAll(ctx context.Context, level string) (map[SubaccountKey]error, error) {
+	if err := validateAuditLevel(level); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.listSubaccountKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[SubaccountKey]error, len(keys))
+	var mu sync.Mutex
+	forEachSubaccount(keys, defaultConcurrency, func(k SubaccountKey) {
+		_, err := s.SetAuditLevel(ctx, k.RegionHost, k.Subaccount, level)
+
+		mu.Lock()
+		results[k] = err
+		mu.Unlock()
+	})
+
+	return results, subaccountErrors(results)
+}
+
+func validateAuditLevel(level string) error {
+	switch level {
+	case AuditLevelSecurity, AuditLevelAll, AuditLevelOff:
+		return nil
+	}
+	return fmt.Errorf("invalid audit level %q", level)
+}
+
+// AuditLogRetention is the rotation and retention setting of the audit log of
+// Cloud Connector.
+type AuditLogRetention struct {
+	MaxFileSizeInMB int `json:"maxFileSizeInMB"` // size at which the log is rotated
+	MaxFiles        int `json:"maxFiles"`        // rotated files kept
+	RetentionDays   int `json:"retentionDays"`   // days after which files are deleted
+}
+
+// GetAuditLogRetention gets the rotation and retention setting of the audit
+// log. It returns ErrNotSupported if the connector does not expose it.
+func (s *CommonService) GetAuditLogRetention(ctx context.Context) (*AuditLogRetention, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/auditLogs/retention", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retention := new(AuditLogRetention)
+	resp, err := s.client.Do(ctx, req, retention)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return retention, resp, nil
+}
+
+// SetAuditLogRetention sets the rotation and retention setting of the audit
+// log and returns the effective setting. All values must be positive. It
+// returns ErrNotSupported if the connector does not expose the setting.
+func (s *CommonService) SetAuditLogRetention(ctx context.Context, retention *AuditLogRetention) (*AuditLogRetention, *Response, error) {
+	if retention.MaxFileSizeInMB < 1 || retention.MaxFiles < 1 || retention.RetentionDays < 1 {
+		return nil, nil, fmt.Errorf("invalid audit log retention %+v", *retention)
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/auditLogs/retention", retention)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(AuditLogRetention)
+	resp, err := s.client.Do(ctx, req, updated)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
@@ -0,0 +1,251 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+type AuthenticationService service
+
+// ErrOldPasswordRejected is returned by ChangeAdminPassword when Cloud
+// Connector does not accept the old password.
+var ErrOldPasswordRejected = errors.New("old password rejected")
+
+// minPasswordLength is the minimum length of a new administrator password.
+const minPasswordLength = 8
+
+// ChangeAdminPassword changes the password of the administrator user. The new
+// password must be at least 8 characters long and contain letters and digits.
+//
+// On success, if the client authenticates with a BasicAuthTransport, its
+// password is updated so that subsequent calls keep working; requests in
+// flight at that moment may still use the old password. A 400 Bad Request
+// whose error identifies the old password as wrong is returned as
+// ErrOldPasswordRejected; other rejections, e.g. of the new password by the
+// connector's password policy, are returned as they are.
+func (s *AuthenticationService) ChangeAdminPassword(ctx context.Context, oldPassword, newPassword string) (*Response, error) {
+	if err := validatePassword(oldPassword, newPassword); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/authentication/basic", struct {
+		OldPassword string `json:"oldPassword"`
+		NewPassword string `json:"newPassword"`
+	}{OldPassword: oldPassword, NewPassword: newPassword})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		if isOldPasswordRejection(err) {
+			return resp, fmt.Errorf("%w: %v", ErrOldPasswordRejected, err)
+		}
+		return resp, err
+	}
+
+	if t := s.client.basicAuthTransport(); t != nil {
+		t.setPassword(newPassword)
+	}
+	return resp, nil
+}
+
+// isOldPasswordRejection reports whether err is a 400 Bad Request whose
+// message or type refers to the old password.
+func isOldPasswordRejection(err error) bool {
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	text := strings.ToLower(errResp.Type + " " + errResp.Message)
+	return strings.Contains(text, "old password") || strings.Contains(text, "old_password") || strings.Contains(text, "oldpassword")
+}
+
+func validatePassword(oldPassword, newPassword string) error {
+	if newPassword == oldPassword {
+		return errors.New("new password must differ from the old password")
+	}
+	if len(newPassword) < minPasswordLength {
+		return fmt.Errorf("new password must be at least %d characters long", minPasswordLength)
+	}
+	var letter, digit bool
+	for _, r := range newPassword {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !letter || !digit {
+		return errors.New("new password must contain letters and digits")
+	}
+	return nil
+}
+
+// ErrOwnSession is returned by RevokeSession when Cloud Connector refuses to
+// revoke the session the request itself was made with.
+var ErrOwnSession = errors.New("cannot revoke own session")
+
+// Session is an active session of the administration UI or API.
+type Session struct {
+	ID           string    `json:"id"`
+	User         string    `json:"user"`
+	SourceIP     string    `json:"sourceIP"`
+	LastActivity Timestamp `json:"lastActivity"`
+}
+
+// ListSessions lists the active administration sessions. It returns
+// ErrNotSupported if the connector does not expose session management.
+func (s *AuthenticationService) ListSessions(ctx context.Context) ([]*Session, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/authentication/sessions", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []*Session
+	resp, err := s.client.Do(ctx, req, &sessions)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return sessions, resp, nil
+}
+
+// RevokeSession terminates the administration session with the given ID. It
+// returns ErrOwnSession if the connector rejects revoking the session of the
+// caller.
+func (s *AuthenticationService) RevokeSession(ctx context.Context, id string) (*Response, error) {
+	if id == "" {
+		return nil, errors.New("session ID must not be empty")
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/connector/authentication/sessions/%v", id)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusConflict {
+		return resp, fmt.Errorf("%w: %v", ErrOwnSession, err)
+	}
+	return resp, err
+}
+
+// LDAPServer is an LDAP server used to authenticate administrators.
+type LDAPServer struct {
+	ID     string `json:"id,omitempty"` // assigned by Cloud Connector
+	URL    string `json:"url"`          // ldap:// or ldaps:// URL
+	BaseDN string `json:"baseDN"`
+
+	UserFilter                string `json:"userFilter,omitempty"`
+	ConnectionTimeoutInMillis int    `json:"connectionTimeoutInMillis,omitempty"`
+
+	// BindUser and BindPassword are the credentials used to search the
+	// directory. BindPassword is write-only: it is never returned by
+	// Cloud Connector and ListLDAPServers always leaves it empty.
+	BindUser     string `json:"bindUser,omitempty"`
+	BindPassword string `json:"bindPassword,omitempty"`
+}
+
+// ListLDAPServers lists the LDAP servers used to authenticate administrators.
+// It returns ErrNotSupported if the connector does not support multiple LDAP
+// servers.
+func (s *AuthenticationService) ListLDAPServers(ctx context.Context) ([]*LDAPServer, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/authentication/ldap/servers", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var servers []*LDAPServer
+	resp, err := s.client.Do(ctx, req, &servers)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, server := range servers {
+		server.BindPassword = ""
+	}
+	return servers, resp, nil
+}
+
+// AddLDAPServer adds an LDAP server used to authenticate administrators
+func (s *AuthenticationService) AddLDAPServer(ctx context.Context, server *LDAPServer) (*LDAPServer, *Response, error) {
+	if err := validateLDAPServer(server); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("POST", "api/v1/configuration/connector/authentication/ldap/servers", server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := new(LDAPServer)
+	resp, err := s.client.Do(ctx, req, created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	created.BindPassword = ""
+	return created, resp, nil
+}
+
+// UpdateLDAPServer updates the LDAP server identified by the ID of server. An
+// empty BindPassword keeps the current password.
+func (s *AuthenticationService) UpdateLDAPServer(ctx context.Context, server *LDAPServer) (*Response, error) {
+	if server.ID == "" {
+		return nil, errors.New("LDAP server ID must not be empty")
+	}
+	if err := validateLDAPServer(server); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/connector/authentication/ldap/servers/%v", server.ID)
+	req, err := s.client.NewRequest("PUT", u, server)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// DeleteLDAPServer removes an LDAP server used to authenticate administrators
+func (s *AuthenticationService) DeleteLDAPServer(ctx context.Context, id string) (*Response, error) {
+	if id == "" {
+		return nil, errors.New("LDAP server ID must not be empty")
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/connector/authentication/ldap/servers/%v", id)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+func validateLDAPServer(server *LDAPServer) error {
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return fmt.Errorf("invalid LDAP server URL %q", server.URL)
+	}
+	if server.BaseDN == "" {
+		return errors.New("LDAP base DN must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAuthenticationService_ChangeAdminPassword_rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantOld bool
+	}{
+		{"old password", `{"type":"ILLEGAL_ARGUMENT","message":"Old password is wrong"}`, true},
+		{"password policy", `{"type":"ILLEGAL_ARGUMENT","message":"New password does not satisfy the password policy"}`, false},
+		{"no body", ``, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mux := setup(t)
+			mux.HandleFunc("/api/v1/configuration/connector/authentication/basic", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "PUT" {
+					t.Errorf("Request method = %v, want PUT", r.Method)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := client.Authentication.ChangeAdminPassword(context.Background(), "manage", "s3cretPassw0rd")
+			if err == nil {
+				t.Fatal("ChangeAdminPassword returned no error")
+			}
+			if got := errors.Is(err, ErrOldPasswordRejected); got != tt.wantOld {
+				t.Errorf("errors.Is(err, ErrOldPasswordRejected) = %v, want %v (err: %v)", got, tt.wantOld, err)
+			}
+			var errResp *ErrorResponse
+			if !errors.As(err, &errResp) && !tt.wantOld {
+				t.Errorf("ChangeAdminPassword error = %v, want *ErrorResponse", err)
+			}
+		})
+	}
+}
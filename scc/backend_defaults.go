@@ -0,0 +1,82 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackendTimeouts are the connector-wide timeouts of requests to backend
+// systems. They apply to every system mapping that does not override them
+// with its own ConnectionTimeoutInSeconds or ResponseTimeoutInSeconds; a
+// mapping's override always takes precedence.
+type BackendTimeouts struct {
+	ConnectionTimeoutInSeconds int `json:"connectionTimeoutInSeconds"`
+	ResponseTimeoutInSeconds   int `json:"responseTimeoutInSeconds"`
+}
+
+// MaxBackendTimeoutInSeconds is the largest backend timeout accepted by
+// SetBackendTimeouts.
+const MaxBackendTimeoutInSeconds = 3600
+
+// GetBackendTimeouts gets the connector-wide timeouts of requests to backend
+// systems
+func (s *CommonService) GetBackendTimeouts(ctx context.Context) (*BackendTimeouts, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/backendTimeouts", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeouts := new(BackendTimeouts)
+	resp, err := s.client.Do(ctx, req, timeouts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return timeouts, resp, nil
+}
+
+// SetBackendTimeouts sets the connector-wide timeouts of requests to backend
+// systems and returns the effective values. Both timeouts must be between 1
+// and MaxBackendTimeoutInSeconds seconds.
+func (s *CommonService) SetBackendTimeouts(ctx context.Context, timeouts *BackendTimeouts) (*BackendTimeouts, *Response, error) {
+	for _, t := range []int{timeouts.ConnectionTimeoutInSeconds, timeouts.ResponseTimeoutInSeconds} {
+		if t < 1 || t > MaxBackendTimeoutInSeconds {
+			return nil, nil, fmt.Errorf("backend timeout of %d seconds out of range [1, %d]", t, MaxBackendTimeoutInSeconds)
+		}
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/backendTimeouts", timeouts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(BackendTimeouts)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	s.client.defaultsMu.Lock()
+	s.client.backendDefaults = updated
+	s.client.defaultsMu.Unlock()
+	return updated, resp, nil
+}
+
+// backendTimeouts returns the connector-wide backend timeouts, reading them
+// on first use.
+func (c *Client) backendTimeouts(ctx context.Context) (*BackendTimeouts, error) {
+	c.defaultsMu.Lock()
+	defer c.defaultsMu.Unlock()
+
+	if c.backendDefaults != nil {
+		return c.backendDefaults, nil
+	}
+
+	timeouts, _, err := c.Common.GetBackendTimeouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.backendDefaults = timeouts
+	return timeouts, nil
+}
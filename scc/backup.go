@@ -1,12 +1,19 @@
 package scc
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 )
 
 type BackupService service
@@ -16,6 +23,10 @@ type BackupService service
 // CreateBackup creates a backup configuration with 'password' as the password used for encrypting sensible data.
 // Only sensitive data in the backup are encrypted with an arbitrary password of your choice. The password is required for the restore operation. The returned ZIP archive itself is not password-protected
 func (s *BackupService) CreateBackup(ctx context.Context, password string, file *os.File) (*Response, error) {
+	return s.createBackup(ctx, password, file)
+}
+
+func (s *BackupService) createBackup(ctx context.Context, password string, w io.Writer) (*Response, error) {
 	req, err := s.client.NewRequest("POST", "api/v1/configuration/backup", struct {
 		Password string `json:"password"`
 	}{Password: password})
@@ -23,7 +34,7 @@ func (s *BackupService) CreateBackup(ctx context.Context, password string, file
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, file)
+	resp, err := s.client.Do(ctx, req, w)
 	if err != nil {
 		return resp, err
 	}
@@ -31,7 +42,9 @@ func (s *BackupService) CreateBackup(ctx context.Context, password string, file
 	return resp, nil
 }
 
-// RestoreBackup restores a backup configuration
+// RestoreBackup restores a backup configuration. If Cloud Connector rejects
+// the media type derived from the file name, ErrUnsupportedMediaType is
+// returned unless WithZipMediaTypeFallback is configured.
 func (s *BackupService) RestoreBackup(ctx context.Context, password string, file *os.File) (*Response, error) {
 	stat, err := file.Stat()
 	if err != nil {
@@ -42,12 +55,139 @@ func (s *BackupService) RestoreBackup(ctx context.Context, password string, file
 	}
 
 	mediaType := mime.TypeByExtension(filepath.Ext(file.Name()))
-	req, err := s.client.NewUploadRequest("PUT", "api/v1/configuration/backup", file, stat.Size(), mediaType)
+	resp, err := s.uploadBackup(ctx, file, stat.Size(), mediaType)
+	if resp != nil && resp.StatusCode == http.StatusUnsupportedMediaType {
+		if !s.client.zipMediaTypeFallback || mediaType == zipMediaType {
+			return resp, fmt.Errorf("%w %q, upload the backup as %v: %v", ErrUnsupportedMediaType, mediaType, zipMediaType, err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return resp, err
+		}
+		resp, err = s.uploadBackup(ctx, file, stat.Size(), zipMediaType)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode != 204 {
+		return resp, errors.New("backup restore failed with status code " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// zipMediaType is the media type Cloud Connector expects for backups.
+const zipMediaType = "application/zip"
+
+// ErrUnsupportedMediaType is returned by RestoreBackup when Cloud Connector
+// rejects the media type derived from the file name of the backup.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// WithZipMediaTypeFallback makes RestoreBackup upload a backup again as
+// application/zip when Cloud Connector rejects the media type derived from its
+// file name, instead of failing with ErrUnsupportedMediaType.
+func WithZipMediaTypeFallback() ClientOption {
+	return func(c *Client) error {
+		c.zipMediaTypeFallback = true
+		return nil
+	}
+}
+
+// uploadBackup uploads the backup read from r. A negative size means the size
+// is unknown and the backup is sent chunked.
+func (s *BackupService) uploadBackup(ctx context.Context, r io.Reader, size int64, mediaType string) (*Response, error) {
+	req, err := s.client.NewUploadRequest("PUT", "api/v1/configuration/backup", r, size, mediaType)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	return s.client.Do(ctx, req, nil)
+}
+
+// A RestoreRollback restores the configuration a connector had before a
+// backup was restored with RestoreBackupWithRollback.
+type RestoreRollback struct {
+	client *Client
+	config *ConnectorConfig
+}
+
+// Config returns the configuration captured before the restore.
+func (r *RestoreRollback) Config() *ConnectorConfig {
+	return r.config
+}
+
+// Rollback re-imports the configuration captured before the restore using
+// ImportConfig.
+func (r *RestoreRollback) Rollback(ctx context.Context) error {
+	return r.client.ImportConfig(ctx, r.config)
+}
+
+// RestoreBackupWithRollback exports the current configuration with
+// ExportConfig, then restores the backup like RestoreBackup. The returned
+// handle can be used to go back to the exported configuration if the restored
+// one turns out to be problematic.
+//
+// A rollback is not a full undo: it is limited to what ExportConfig captures,
+// it does not remove anything the restore added and, since secrets are not
+// exported, it cannot re-create subaccounts the restore removed unless their
+// credentials are filled into Config before calling Rollback.
+func (s *BackupService) RestoreBackupWithRollback(ctx context.Context, password string, file *os.File) (*RestoreRollback, *Response, error) {
+	config, err := s.client.ExportConfig(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.RestoreBackup(ctx, password, file)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &RestoreRollback{client: s.client, config: config}, resp, nil
+}
+
+// CloneConfig copies the configuration of the connector src to the connector
+// dst by creating a backup on src, encrypted with password, and restoring it on
+// dst. The backup is streamed from one connector to the other without being
+// buffered in memory or stored on disk, so its size is not known up front and
+// it is uploaded chunked as application/zip. As a streamed upload cannot be
+// repeated, the restore is not retried.
+//
+// If either side fails, the other request is aborted and the first error is
+// returned. The returned Response is the one of the restore on dst.
+func CloneConfig(ctx context.Context, src, dst *Client, password string) (*Response, error) {
+	srcCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	created := make(chan error, 1)
+	go func() {
+		_, err := src.Backup.createBackup(srcCtx, password, pw)
+		// Failures caused by aborting the creation below are not reported,
+		// the error of the restore is. This is checked before closing the
+		// pipe, which makes the restore fail and abort the creation.
+		aborted := srcCtx.Err() != nil || errors.Is(err, errRestoreFinished)
+		pw.CloseWithError(err)
+		if err != nil && !aborted {
+			created <- fmt.Errorf("creating backup: %w", err)
+			return
+		}
+		created <- nil
+	}()
+
+	resp, err := dst.Backup.uploadBackup(ctx, pr, -1, zipMediaType)
+	// Unblock the backup creation if the restore did not read all of it.
+	pr.CloseWithError(errRestoreFinished)
+	if err != nil {
+		cancel()
+	}
+	createErr := <-created
+	if resp != nil && resp.StatusCode == http.StatusUnsupportedMediaType {
+		return resp, fmt.Errorf("%w %q: %v", ErrUnsupportedMediaType, zipMediaType, err)
+	}
+	// A failed backup creation also fails the restore, so report its cause.
+	if createErr != nil {
+		return resp, createErr
+	}
 	if err != nil {
 		return resp, err
 	}
@@ -58,3 +198,70 @@ func (s *BackupService) RestoreBackup(ctx context.Context, password string, file
 
 	return resp, nil
 }
+
+var errRestoreFinished = errors.New("restore finished before the backup was read")
+
+// zipSignature is the signature every ZIP archive starts with.
+var zipSignature = []byte("PK\x03\x04")
+
+// backupDirectories are the configuration directories of a Cloud Connector
+// installation, each of which a backup contains.
+var backupDirectories = []string{"config", "config_master", "scc_config"}
+
+// ValidateBackupFile checks locally that file looks like a backup created by
+// CreateBackup before it is uploaded with RestoreBackup: it must be a complete
+// ZIP archive containing the configuration directories of Cloud Connector,
+// and all entries must be readable with matching checksums. This catches
+// wrong files and truncated downloads; the encrypted content itself can only
+// be verified by Cloud Connector.
+func ValidateBackupFile(file *os.File) error {
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return errors.New("the backup file can't be a directory")
+	}
+
+	signature := make([]byte, len(zipSignature))
+	if _, err := file.ReadAt(signature, 0); err != nil || !bytes.Equal(signature, zipSignature) {
+		return errors.New("backup file " + file.Name() + " is not a ZIP archive")
+	}
+
+	archive, err := zip.NewReader(file, stat.Size())
+	if err != nil {
+		return errors.New("backup file " + file.Name() + " is corrupt: " + err.Error())
+	}
+	if len(archive.File) == 0 {
+		return errors.New("backup file " + file.Name() + " is empty")
+	}
+	for _, dir := range backupDirectories {
+		if !hasZipDirectory(archive, dir) {
+			return errors.New("backup file " + file.Name() + " has no " + dir + " directory")
+		}
+	}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			return errors.New("backup file " + file.Name() + " is corrupt: " + err.Error())
+		}
+		_, err = io.Copy(ioutil.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return errors.New("backup file " + file.Name() + " is corrupt: entry " + f.Name + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// hasZipDirectory reports whether archive has an entry in the top-level
+// directory dir.
+func hasZipDirectory(archive *zip.Reader, dir string) bool {
+	for _, f := range archive.File {
+		if f.Name == dir || strings.HasPrefix(f.Name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
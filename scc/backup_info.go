@@ -0,0 +1,49 @@
+package scc
+
+import (
+	"context"
+	"net/http"
+)
+
+// BackupInfo is the metadata of the backup Cloud Connector would create.
+type BackupInfo struct {
+	Size             int64     `json:"size"` // in bytes, -1 if unknown
+	CreationTime     Timestamp `json:"creationTime"`
+	ConnectorVersion string    `json:"connectorVersion,omitempty"`
+}
+
+// GetBackupInfo gets the metadata of a backup without downloading it. If the
+// connector has no dedicated endpoint for it, the size and creation time are
+// taken from the Content-Length and Last-Modified headers of a HEAD request
+// instead, and ConnectorVersion is left empty. CreationTime is zero if the connector reports none.
+func (s *BackupService) GetBackupInfo(ctx context.Context) (*BackupInfo, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/backup/info", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := new(BackupInfo)
+	resp, err := s.client.Do(ctx, req, info)
+	if err == nil {
+		return info, resp, nil
+	}
+	if !isNotFound(err) {
+		return nil, resp, err
+	}
+
+	req, err = s.client.NewRequest("HEAD", "api/v1/configuration/backup", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err = s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	info = &BackupInfo{Size: resp.ContentLength}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.CreationTime = Timestamp{t}
+	}
+	return info, resp, nil
+}
@@ -0,0 +1,89 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// BackupSchedule is the configuration of scheduled automatic backups.
+type BackupSchedule struct {
+	Enabled         bool              `json:"enabled"`
+	IntervalInHours int               `json:"intervalInHours"`
+	Destination     BackupDestination `json:"destination"`
+	Retention       BackupRetention   `json:"retention"`
+}
+
+// BackupDestination is where scheduled backups are stored.
+type BackupDestination struct {
+	Directory string `json:"directory"` // directory on the connector host
+}
+
+// BackupRetention limits how many scheduled backups are kept. Zero values
+// mean no limit.
+type BackupRetention struct {
+	MaxBackups   int `json:"maxBackups"`
+	MaxAgeInDays int `json:"maxAgeInDays"`
+}
+
+// GetBackupSchedule gets the configuration of scheduled backups. It returns
+// ErrNotSupported if the connector does not support scheduled backups.
+func (s *BackupService) GetBackupSchedule(ctx context.Context) (*BackupSchedule, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/backup/schedule", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedule := new(BackupSchedule)
+	resp, err := s.client.Do(ctx, req, schedule)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return schedule, resp, nil
+}
+
+// SetBackupSchedule sets the configuration of scheduled backups. An enabled
+// schedule needs an interval of at least one hour and a destination
+// directory. It returns ErrNotSupported if the connector does not support
+// scheduled backups.
+func (s *BackupService) SetBackupSchedule(ctx context.Context, schedule *BackupSchedule) (*BackupSchedule, *Response, error) {
+	if err := validateBackupSchedule(schedule); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/backup/schedule", schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(BackupSchedule)
+	resp, err := s.client.Do(ctx, req, updated)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
+
+func validateBackupSchedule(schedule *BackupSchedule) error {
+	if schedule.Retention.MaxBackups < 0 || schedule.Retention.MaxAgeInDays < 0 {
+		return fmt.Errorf("invalid backup retention %+v", schedule.Retention)
+	}
+	if !schedule.Enabled {
+		return nil
+	}
+	if schedule.IntervalInHours < 1 {
+		return fmt.Errorf("invalid backup interval of %d hours", schedule.IntervalInHours)
+	}
+	if schedule.Destination.Directory == "" {
+		return errors.New("backup destination directory must not be empty")
+	}
+	return nil
+}
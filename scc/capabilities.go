@@ -0,0 +1,83 @@
+package scc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Protocols of system mappings.
+var mappingProtocols = []string{"HTTP", "HTTPS", "RFC", "RFCS", "LDAP", "LDAPS", "TCP", "TCPSSL"}
+
+// Capabilities lists what a Cloud Connector instance supports.
+type Capabilities struct {
+	Protocols    []string      `json:"protocols"`
+	BackendTypes []BackendType `json:"backendTypes"`
+	ChannelTypes []ChannelType `json:"channelTypes"`
+
+	// Inferred reports that the connector has no capability endpoint and
+	// the capabilities were derived from its version.
+	Inferred bool `json:"-"`
+}
+
+// channelTypeSince is the first connector version supporting a channel type,
+// for types that are not supported by all versions.
+var channelTypeSince = map[ChannelType][2]int{
+	ChannelTypeK8SCluster: {2, 15},
+}
+
+// GetCapabilities gets the mapping protocols, backend types and service
+// channel types supported by Cloud Connector. Connectors without a capability
+// endpoint are assumed to support everything their version supports.
+func (s *CommonService) GetCapabilities(ctx context.Context) (*Capabilities, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/connector/capabilities", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	capabilities := new(Capabilities)
+	resp, err := s.client.Do(ctx, req, capabilities)
+	if err == nil {
+		return capabilities, resp, nil
+	}
+	if !isNotFound(err) {
+		return nil, resp, err
+	}
+
+	version, resp, err := s.GetVersion(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	capabilities = &Capabilities{
+		Protocols:    append([]string(nil), mappingProtocols...),
+		BackendTypes: BackendTypes(),
+		Inferred:     true,
+	}
+	major, minor, ok := parseVersion(version.Version)
+	for _, t := range channelTypes {
+		if since, limited := channelTypeSince[t]; limited && ok && (major < since[0] || major == since[0] && minor < since[1]) {
+			continue
+		}
+		capabilities.ChannelTypes = append(capabilities.ChannelTypes, t)
+	}
+	return capabilities, resp, nil
+}
+
+// parseVersion returns the major and minor number of a version such as
+// "2.14.1".
+func parseVersion(version string) (major, minor int, ok bool) {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
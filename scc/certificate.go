@@ -0,0 +1,320 @@
+package scc
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CertificateService service
+
+// Certificate is a certificate in both PEM encoded and parsed form.
+type Certificate struct {
+	PEM         []byte
+	Certificate *x509.Certificate
+}
+
+// Subject returns the distinguished name of the certificate's subject.
+func (c *Certificate) Subject() string {
+	return c.Certificate.Subject.String()
+}
+
+// Issuer returns the distinguished name of the certificate's issuer.
+func (c *Certificate) Issuer() string {
+	return c.Certificate.Issuer.String()
+}
+
+// NotBefore returns the start of the certificate's validity period.
+func (c *Certificate) NotBefore() time.Time {
+	return c.Certificate.NotBefore
+}
+
+// NotAfter returns the end of the certificate's validity period.
+func (c *Certificate) NotAfter() time.Time {
+	return c.Certificate.NotAfter
+}
+
+// GetTunnelCertificateChain gets the certificate chain Cloud Connector uses
+// for the tunnel to SAP BTP, ordered from the connector's own certificate to
+// the root
+func (s *CertificateService) GetTunnelCertificateChain(ctx context.Context) ([]*Certificate, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/tunnel/certificateChain", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, resp, err := s.client.DoRaw(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	chain, err := parseCertificates(data)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return chain, resp, nil
+}
+
+// parseCertificates parses all PEM encoded certificates in data, in order.
+func parseCertificates(data []byte) ([]*Certificate, error) {
+	var certs []*Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, &Certificate{PEM: pem.EncodeToMemory(block), Certificate: cert})
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// GenerateCSR lets Cloud Connector create a new key pair for its system
+// certificate and returns the PEM encoded certificate signing request for it.
+// subject is a distinguished name such as "CN=scc.example.com,O=Example" and
+// must contain a CN; sans lists the DNS names and IP addresses to include as
+// subject alternative names.
+func (s *CertificateService) GenerateCSR(ctx context.Context, subject string, sans []string) ([]byte, *Response, error) {
+	if err := validateDN(subject); err != nil {
+		return nil, nil, err
+	}
+	type subjectAltName struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	altNames := make([]subjectAltName, 0, len(sans))
+	for _, san := range sans {
+		switch {
+		case net.ParseIP(san) != nil:
+			altNames = append(altNames, subjectAltName{Type: "IP", Value: san})
+		case isDNSName(san):
+			altNames = append(altNames, subjectAltName{Type: "DNS", Value: san})
+		default:
+			return nil, nil, fmt.Errorf("invalid subject alternative name %q", san)
+		}
+	}
+
+	req, err := s.client.NewRequest("POST", "api/v1/configuration/connector/systemCertificate/csr", struct {
+		SubjectDN       string           `json:"subjectDN"`
+		SubjectAltNames []subjectAltName `json:"subjectAltNames,omitempty"`
+	}{SubjectDN: subject, SubjectAltNames: altNames})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, resp, err := s.client.DoRaw(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, resp, errors.New("no PEM encoded certificate request found")
+	}
+	if _, err := x509.ParseCertificateRequest(block.Bytes); err != nil {
+		return nil, resp, err
+	}
+
+	return pem.EncodeToMemory(block), resp, nil
+}
+
+// validateDN checks that dn is a comma-separated list of attribute=value
+// pairs that includes a CN.
+func validateDN(dn string) error {
+	var cn bool
+	for _, rdn := range strings.Split(dn, ",") {
+		i := strings.Index(rdn, "=")
+		if i <= 0 || strings.TrimSpace(rdn[i+1:]) == "" {
+			return fmt.Errorf("invalid distinguished name %q", dn)
+		}
+		if strings.EqualFold(strings.TrimSpace(rdn[:i]), "CN") {
+			cn = true
+		}
+	}
+	if !cn {
+		return fmt.Errorf("distinguished name %q has no CN", dn)
+	}
+	return nil
+}
+
+// isDNSName reports whether name is a syntactically valid DNS name, possibly
+// with a leading wildcard label.
+func isDNSName(name string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Kinds of certificates returned by ListAllCertificates.
+const (
+	CertificateKindSystem     = "system"     // system certificate of the administration UI
+	CertificateKindCA         = "ca"         // CA certificate for principal propagation
+	CertificateKindTunnel     = "tunnel"     // tunnel certificate chain
+	CertificateKindSubaccount = "subaccount" // certificate of a subaccount tunnel
+	CertificateKindTrustedCA  = "trustedCA"  // CA trusted for principal propagation
+)
+
+// ManagedCertificate is a certificate managed by Cloud Connector.
+type ManagedCertificate struct {
+	Kind       string
+	Subaccount *SubaccountKey // set for CertificateKindSubaccount and trusted CAs of a subaccount
+	Subject    string
+	Issuer     string
+	NotAfter   time.Time // zero for trusted CAs, whose validity is not reported
+}
+
+// ListAllCertificates lists the certificates managed by Cloud Connector, e.g.
+// to track their expiry: the system certificate, the CA certificate, the
+// tunnel certificate chain, the certificate of each subaccount and the CAs
+// trusted for principal propagation, by the connector and by each subaccount.
+// The components are read concurrently. Certificates that are not configured
+// are left out; components that cannot be read are reported in a *MultiError
+// keyed by component kind or subaccount, returned together with the
+// certificates that could be read. The trusted CAs of a subaccount are keyed
+// separately, as "<region host>/<subaccount>/trustedCA", so that failing to
+// read them does not hide the subaccount certificate. The connector does not report the validity
+// of trusted CAs, so their NotAfter is zero.
+func (s *CertificateService) ListAllCertificates(ctx context.Context) ([]*ManagedCertificate, error) {
+	var mu sync.Mutex
+	var all []*ManagedCertificate
+	errs := make(map[string]error)
+	collect := func(component string, certs []*ManagedCertificate, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[component] = err
+			return
+		}
+		all = append(all, certs...)
+	}
+
+	var wg sync.WaitGroup
+	pemComponents := map[string]string{
+		CertificateKindSystem: "api/v1/configuration/connector/ui/systemCertificate",
+		CertificateKindCA:     "api/v1/configuration/connector/onPremise/ppCaCertificate",
+	}
+	for kind, urlStr := range pemComponents {
+		wg.Add(1)
+		go func(kind, urlStr string) {
+			defer wg.Done()
+			certs, err := s.getCertificates(ctx, urlStr)
+			if isNotFound(err) {
+				return
+			}
+			collect(kind, managedCertificates(kind, certs), err)
+		}(kind, urlStr)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		chain, _, err := s.GetTunnelCertificateChain(ctx)
+		collect(CertificateKindTunnel, managedCertificates(CertificateKindTunnel, chain), err)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cas, _, err := s.client.PrincipalPropagation.ListTrustedCAs(ctx)
+		if errors.Is(err, ErrNotSupported) {
+			return
+		}
+		collect(CertificateKindTrustedCA, trustedCACertificates(nil, cas), err)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		keys, err := s.client.Subaccount.listSubaccountKeys(ctx)
+		if err != nil {
+			collect(CertificateKindSubaccount, nil, err)
+			return
+		}
+		forEachSubaccount(keys, defaultConcurrency, func(k SubaccountKey) {
+			sa, _, err := s.client.Subaccount.GetSubaccount(ctx, k.RegionHost, k.Subaccount)
+			if err != nil {
+				collect(k.String(), nil, err)
+				return
+			}
+			if sa.Tunnel != nil && sa.Tunnel.SubaccountCertificate != nil {
+				cert := sa.Tunnel.SubaccountCertificate
+				collect(k.String(), []*ManagedCertificate{{
+					Kind:       CertificateKindSubaccount,
+					Subaccount: &k,
+					Subject:    cert.SubjectDN,
+					Issuer:     cert.IssuerDN,
+					NotAfter:   cert.NotAfter.Time,
+				}}, nil)
+			}
+
+			cas, _, err := s.client.PrincipalPropagation.ListSubaccountTrustedCAs(ctx, k.RegionHost, k.Subaccount)
+			collect(k.String()+"/"+CertificateKindTrustedCA, trustedCACertificates(&k, cas), err)
+		})
+	}()
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return all, &MultiError{errs: errs}
+	}
+	return all, nil
+}
+
+// getCertificates gets the PEM encoded certificates at urlStr.
+func (s *CertificateService) getCertificates(ctx context.Context, urlStr string) ([]*Certificate, error) {
+	req, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := s.client.DoRaw(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificates(data)
+}
+
+func managedCertificates(kind string, certs []*Certificate) []*ManagedCertificate {
+	managed := make([]*ManagedCertificate, len(certs))
+	for i, c := range certs {
+		managed[i] = &ManagedCertificate{Kind: kind, Subject: c.Subject(), Issuer: c.Issuer(), NotAfter: c.NotAfter()}
+	}
+	return managed
+}
+
+// trustedCACertificates returns cas as certificates of kind
+// CertificateKindTrustedCA, trusted by the subaccount k or, if k is nil, by
+// the connector.
+func trustedCACertificates(k *SubaccountKey, cas []*TrustedCA) []*ManagedCertificate {
+	managed := make([]*ManagedCertificate, len(cas))
+	for i, ca := range cas {
+		managed[i] = &ManagedCertificate{Kind: CertificateKindTrustedCA, Subaccount: k, Subject: ca.Subject, Issuer: ca.Issuer}
+	}
+	return managed
+}
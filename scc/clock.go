@@ -0,0 +1,63 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// MaxClockSkew is the clock difference between Cloud Connector and the local
+// host above which ConnectorTime reports SkewExceeded. Certificates issued for
+// principal propagation are short-lived, so larger differences cause
+// authentication failures.
+const MaxClockSkew = 30 * time.Second
+
+// ConnectorTime is the current time of Cloud Connector as seen from the
+// client.
+type ConnectorTime struct {
+	Time time.Time
+
+	// Skew is the connector time minus the local time at which the
+	// connector answered, estimated as the middle of the request. The Date
+	// header the time is read from has a resolution of one second.
+	Skew time.Duration
+
+	// SkewExceeded reports whether the absolute skew exceeds MaxClockSkew.
+	SkewExceeded bool
+}
+
+// GetConnectorTime gets the current time of Cloud Connector from the Date
+// header of a lightweight request and computes its skew against the local
+// clock.
+func (s *CommonService) GetConnectorTime(ctx context.Context) (*ConnectorTime, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/connector/version", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sent := time.Now()
+	resp, err := s.client.Do(ctx, req, ioutil.Discard)
+	if err != nil {
+		return nil, resp, err
+	}
+	received := time.Now()
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return nil, resp, errors.New("response has no Date header")
+	}
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := t.Sub(local)
+	return &ConnectorTime{
+		Time:         t,
+		Skew:         skew,
+		SkewExceeded: skew > MaxClockSkew || skew < -MaxClockSkew,
+	}, resp, nil
+}
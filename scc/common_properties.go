@@ -2,6 +2,8 @@ package scc
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 )
 
 type CommonService service
@@ -35,19 +37,24 @@ func (s *CommonService) GetCommonProperties(ctx context.Context) (*CommonPropert
 	return commonProperties, resp, nil
 }
 
-// GetVersion gets the version of Cloud Connector
+// GetVersion gets the version of Cloud Connector. Some connector builds return
+// the version as plain text instead of JSON; both forms are accepted.
 func (s *CommonService) GetVersion(ctx context.Context) (*Version, *Response, error) {
 	req, err := s.client.NewRequest("GET", "api/v1/connector/version", nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	version := new(Version)
-	resp, err := s.client.Do(ctx, req, version)
+	data, resp, err := s.client.DoRaw(ctx, req, nil)
 	if err != nil {
 		return nil, resp, err
 	}
 
+	version := new(Version)
+	if err := json.Unmarshal(data, version); err != nil {
+		version.Version = trimTextBody(data)
+	}
+
 	return version, resp, nil
 }
 
@@ -68,3 +75,16 @@ func (s *CommonService) SetDescription(ctx context.Context, description string)
 
 	return commonProperties, resp, nil
 }
+
+// DetectVersionChange reports whether the version of Cloud Connector differs
+// from knownVersion, e.g. after an automatic update, and returns the current
+// version. Surrounding whitespace is ignored when comparing.
+func (s *CommonService) DetectVersionChange(ctx context.Context, knownVersion string) (bool, string, *Response, error) {
+	version, resp, err := s.GetVersion(ctx)
+	if err != nil {
+		return false, "", resp, err
+	}
+
+	current := strings.TrimSpace(version.Version)
+	return current != strings.TrimSpace(knownVersion), current, resp, nil
+}
@@ -0,0 +1,51 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCommonService_GetVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", `{"version":"2.15.0"}`},
+		{"plain text", "text/plain", "2.15.0\n"},
+		{"quoted", "text/plain", `"2.15.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mux := setup(t)
+			mux.HandleFunc("/api/v1/connector/version", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" {
+					t.Errorf("Request method = %v, want GET", r.Method)
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				fmt.Fprint(w, tt.body)
+			})
+
+			version, _, err := client.Common.GetVersion(context.Background())
+			if err != nil {
+				t.Fatalf("GetVersion returned error: %v", err)
+			}
+			if version.Version != "2.15.0" {
+				t.Errorf("GetVersion returned %q, want %q", version.Version, "2.15.0")
+			}
+		})
+	}
+}
+
+func TestCommonService_GetVersion_tooLarge(t *testing.T) {
+	client, mux := setup(t, WithMaxResponseBytes(8))
+	mux.HandleFunc("/api/v1/connector/version", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"2.15.0"}`)
+	})
+
+	if _, _, err := client.Common.GetVersion(context.Background()); err != ErrResponseTooLarge {
+		t.Errorf("GetVersion returned error %v, want %v", err, ErrResponseTooLarge)
+	}
+}
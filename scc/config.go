@@ -0,0 +1,381 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ConnectorConfig is a structured, human-readable snapshot of the
+// configuration of a Cloud Connector. Unlike the ZIP archive created by
+// BackupService it is not encrypted and is suited for diffing and version
+// control. Runtime state such as tunnel state is not part of it.
+type ConnectorConfig struct {
+	Common      *CommonProperties   `json:"common"`
+	HA          *HAConfig           `json:"ha"`
+	Subaccounts []*SubaccountConfig `json:"subaccounts"`
+}
+
+// HAConfig is the high availability part of a ConnectorConfig. Master is only
+// set when the connector has the master role.
+type HAConfig struct {
+	Role   string               `json:"role"`
+	Master *MasterConfiguration `json:"master,omitempty"`
+}
+
+// SubaccountConfig is the configuration of a single subaccount within a
+// ConnectorConfig.
+type SubaccountConfig struct {
+	*Subaccount
+	AuditLevel      string                            `json:"auditLevel,omitempty"`
+	SystemMappings  []*SystemMappingConfig            `json:"systemMappings"`
+	ServiceChannels map[ChannelType][]*ServiceChannel `json:"serviceChannels"`
+}
+
+// SystemMappingConfig is a system mapping together with its resources.
+type SystemMappingConfig struct {
+	*SystemMapping
+	Resources []*SystemMappingResource `json:"resources"`
+}
+
+// ExportConfig assembles the configuration of the connector from the
+// individual configuration endpoints, which are called concurrently.
+func (c *Client) ExportConfig(ctx context.Context) (*ConnectorConfig, error) {
+	config := new(ConnectorConfig)
+	var subaccounts []*Subaccount
+
+	g := new(group)
+	g.Go(func() (err error) {
+		config.Common, _, err = c.Common.GetCommonProperties(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		config.HA, err = c.exportHA(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		subaccounts, _, err = c.Subaccount.ListSubaccounts(ctx)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	config.Subaccounts = make([]*SubaccountConfig, len(subaccounts))
+	for i, sa := range subaccounts {
+		i, sa := i, sa
+		g.Go(func() (err error) {
+			config.Subaccounts[i], err = c.exportSubaccount(ctx, sa)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ExportSubaccountConfig assembles the configuration of a single subaccount,
+// i.e. the part of ExportConfig that belongs to it. It can be applied to
+// another subaccount or connector with ImportSubaccountConfig.
+func (c *Client) ExportSubaccountConfig(ctx context.Context, regionHost, subaccount string) (*SubaccountConfig, error) {
+	sa, _, err := c.Subaccount.GetSubaccount(ctx, regionHost, subaccount)
+	if err != nil {
+		return nil, err
+	}
+	return c.exportSubaccount(ctx, sa)
+}
+
+func (c *Client) exportHA(ctx context.Context) (*HAConfig, error) {
+	role, _, err := c.HA.GetHASettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ha := &HAConfig{Role: role}
+	if role == HARoleMaster {
+		if ha.Master, _, err = c.HA.GetMasterConfiguration(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return ha, nil
+}
+
+// exportSubaccount reads the configuration belonging to sa. The tunnel state
+// of sa is dropped as it is not configuration.
+func (c *Client) exportSubaccount(ctx context.Context, sa *Subaccount) (*SubaccountConfig, error) {
+	saCopy := *sa
+	saCopy.Tunnel = nil
+	config := &SubaccountConfig{
+		Subaccount:      &saCopy,
+		ServiceChannels: make(map[ChannelType][]*ServiceChannel),
+	}
+	var mu sync.Mutex
+
+	g := new(group)
+	g.Go(func() error {
+		level, _, err := c.Subaccount.GetAuditLevel(ctx, sa.RegionHost, sa.Subaccount)
+		if err != nil {
+			return err
+		}
+		config.AuditLevel = level.AuditLevel
+		return nil
+	})
+	g.Go(func() error {
+		mappings, _, err := c.SystemMapping.ListSystemMappings(ctx, sa.RegionHost, sa.Subaccount)
+		if err != nil {
+			return err
+		}
+		config.SystemMappings = make([]*SystemMappingConfig, len(mappings))
+		for i, m := range mappings {
+			resources, _, err := c.SystemMapping.ListSystemMappingResources(ctx, sa.RegionHost, sa.Subaccount, m.VirtualHost, m.VirtualPort)
+			if err != nil {
+				return err
+			}
+			config.SystemMappings[i] = &SystemMappingConfig{SystemMapping: m, Resources: resources}
+		}
+		return nil
+	})
+	for _, channelType := range channelTypes {
+		channelType := channelType
+		g.Go(func() error {
+			channels, _, err := c.ServiceChannel.ListServiceChannels(ctx, sa.RegionHost, sa.Subaccount, channelType)
+			if err != nil {
+				return err
+			}
+			if len(channels) > 0 {
+				mu.Lock()
+				config.ServiceChannels[channelType] = channels
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ImportConfig applies config to the connector. Subaccounts, system mappings,
+// resources and service channels missing on the connector are created,
+// existing ones are updated; nothing is deleted. Subaccounts are imported
+// concurrently. The high availability role is never changed.
+//
+// Creating a subaccount requires its CloudUser and CloudPassword, which are
+// not part of exported configurations and must be filled in by the caller.
+func (c *Client) ImportConfig(ctx context.Context, config *ConnectorConfig) error {
+	if err := validateSubaccountConfigs(config.Subaccounts); err != nil {
+		return err
+	}
+	if config.Common != nil {
+		if _, _, err := c.Common.SetDescription(ctx, config.Common.Description); err != nil {
+			return err
+		}
+	}
+	if config.HA != nil && config.HA.Master != nil {
+		if _, _, err := c.HA.SetMasterConfiguration(ctx, config.HA.Master); err != nil {
+			return err
+		}
+	}
+
+	g := new(group)
+	sem := make(chan struct{}, defaultConcurrency)
+	for _, sa := range config.Subaccounts {
+		sa := sa
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			return c.importSubaccount(ctx, sa)
+		})
+	}
+	return g.Wait()
+}
+
+// ImportSubaccountOptions configures ImportSubaccountConfig.
+type ImportSubaccountOptions struct {
+	// RegionHost and Subaccount, if set, replace those of the imported
+	// configuration, e.g. to clone a subaccount's configuration to another
+	// subaccount.
+	RegionHost string
+	Subaccount string
+
+	// CloudUser and CloudPassword, if set, are used when the subaccount has
+	// to be created.
+	CloudUser     string
+	CloudPassword string
+}
+
+// ImportSubaccountConfig applies the configuration of a single subaccount, as
+// exported with ExportSubaccountConfig, like ImportConfig does: the
+// subaccount, its system mappings, resources and service channels are created
+// or updated and its audit level is set. opts may be nil; config itself is
+// not modified.
+func (c *Client) ImportSubaccountConfig(ctx context.Context, config *SubaccountConfig, opts *ImportSubaccountOptions) error {
+	if opts == nil {
+		opts = &ImportSubaccountOptions{}
+	}
+	if err := validateSubaccountConfigs([]*SubaccountConfig{config}); err != nil {
+		return err
+	}
+
+	sa := *config.Subaccount
+	if opts.RegionHost != "" {
+		sa.RegionHost = opts.RegionHost
+	}
+	if opts.Subaccount != "" {
+		sa.Subaccount = opts.Subaccount
+	}
+	if opts.CloudUser != "" {
+		sa.CloudUser = opts.CloudUser
+	}
+	if opts.CloudPassword != "" {
+		sa.CloudPassword = opts.CloudPassword
+	}
+	if err := validateSubaccountKey(sa.RegionHost, sa.Subaccount); err != nil {
+		return err
+	}
+
+	remapped := *config
+	remapped.Subaccount = &sa
+	return c.importSubaccount(ctx, &remapped)
+}
+
+// validateSubaccountConfigs checks that configs can be imported, i.e. that
+// none of them lacks its subaccount, as hand-written or partially decoded
+// configurations may.
+func validateSubaccountConfigs(configs []*SubaccountConfig) error {
+	for i, config := range configs {
+		if config == nil || config.Subaccount == nil {
+			return fmt.Errorf("subaccount configuration %d has no subaccount", i)
+		}
+	}
+	return nil
+}
+
+func (c *Client) importSubaccount(ctx context.Context, config *SubaccountConfig) error {
+	rh, sa := config.RegionHost, config.Subaccount.Subaccount
+	_, _, err := c.Subaccount.GetSubaccount(ctx, rh, sa)
+	switch {
+	case isNotFound(err):
+		_, _, err = c.Subaccount.AddSubaccount(ctx, config.Subaccount)
+	case err == nil:
+		_, _, err = c.Subaccount.UpdateSubaccount(ctx, rh, sa, config.Subaccount)
+	}
+	if err != nil {
+		return err
+	}
+
+	if config.AuditLevel != "" {
+		if _, err := c.Subaccount.SetAuditLevel(ctx, rh, sa, config.AuditLevel); err != nil {
+			return err
+		}
+	}
+
+	existing, _, err := c.SystemMapping.ListSystemMappings(ctx, rh, sa)
+	if err != nil {
+		return err
+	}
+	existingMappings := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		existingMappings[m.VirtualHost+":"+m.VirtualPort] = true
+	}
+	for _, m := range config.SystemMappings {
+		if err := c.importSystemMapping(ctx, rh, sa, m, existingMappings[m.VirtualHost+":"+m.VirtualPort]); err != nil {
+			return err
+		}
+	}
+
+	for channelType, channels := range config.ServiceChannels {
+		if err := c.importServiceChannels(ctx, rh, sa, channelType, channels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) importSystemMapping(ctx context.Context, rh, sa string, config *SystemMappingConfig, exists bool) error {
+	vh, vp := config.VirtualHost, config.VirtualPort
+	existing := make(map[string]bool)
+	if exists {
+		if _, err := c.SystemMapping.UpdateSystemMapping(ctx, rh, sa, config.SystemMapping); err != nil {
+			return err
+		}
+		resources, _, err := c.SystemMapping.ListSystemMappingResources(ctx, rh, sa, vh, vp)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			existing[r.ID] = true
+		}
+	} else if _, err := c.SystemMapping.CreateSystemMapping(ctx, rh, sa, config.SystemMapping); err != nil {
+		return err
+	}
+
+	for _, r := range config.Resources {
+		var err error
+		if existing[r.ID] {
+			_, err = c.SystemMapping.UpdateSystemMappingResource(ctx, rh, sa, vh, vp, r)
+		} else {
+			_, err = c.SystemMapping.CreateSystemMappingResource(ctx, rh, sa, vh, vp, r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importServiceChannels creates or updates channels. As channel IDs are
+// assigned by the connector, channels are matched by their details.
+func (c *Client) importServiceChannels(ctx context.Context, rh, sa string, channelType ChannelType, channels []*ServiceChannel) error {
+	existing, _, err := c.ServiceChannel.ListServiceChannels(ctx, rh, sa, channelType)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]int, len(existing))
+	for _, ch := range existing {
+		ids[ch.Details] = ch.ID
+	}
+
+	for _, ch := range channels {
+		chCopy := *ch
+		chCopy.State = nil
+		if id, ok := ids[ch.Details]; ok {
+			chCopy.ID = id
+			_, err = c.ServiceChannel.UpdateServiceChannel(ctx, rh, sa, channelType, &chCopy)
+		} else {
+			_, err = c.ServiceChannel.CreateServiceChannel(ctx, rh, sa, channelType, &chCopy)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// group runs functions concurrently and keeps the first error they return.
+type group struct {
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+func (g *group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.errOnce.Do(func() { g.err = err })
+		}
+	}()
+}
+
+// Wait blocks until all functions started with Go have returned and returns
+// the first error encountered, if any.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
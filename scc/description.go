@@ -0,0 +1,65 @@
+package scc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// splitDescription splits a description into its human-readable prefix and
+// the metadata encoded as a trailing JSON object. A description without such
+// an object yields the trimmed description and nil metadata.
+func splitDescription(description string) (string, map[string]string) {
+	for i := strings.Index(description, "{"); i >= 0; {
+		var metadata map[string]string
+		if err := json.Unmarshal([]byte(description[i:]), &metadata); err == nil {
+			return strings.TrimSpace(description[:i]), metadata
+		}
+		next := strings.Index(description[i+1:], "{")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return strings.TrimSpace(description), nil
+}
+
+// GetDescriptionMetadata gets the metadata encoded as a JSON object at the end
+// of the description of Cloud Connector. A description without metadata
+// yields an empty map.
+func (s *CommonService) GetDescriptionMetadata(ctx context.Context) (map[string]string, *Response, error) {
+	properties, resp, err := s.GetCommonProperties(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	_, metadata := splitDescription(properties.Description)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return metadata, resp, nil
+}
+
+// SetDescriptionMetadata replaces the metadata encoded at the end of the
+// description of Cloud Connector with metadata, keeping the human-readable
+// text before it. An empty map removes the metadata.
+func (s *CommonService) SetDescriptionMetadata(ctx context.Context, metadata map[string]string) (*CommonProperties, *Response, error) {
+	properties, resp, err := s.GetCommonProperties(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	description, _ := splitDescription(properties.Description)
+	if len(metadata) > 0 {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, resp, err
+		}
+		if description != "" {
+			description += " "
+		}
+		description += string(encoded)
+	}
+
+	return s.SetDescription(ctx, description)
+}
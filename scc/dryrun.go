@@ -0,0 +1,112 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxBodySummary is the maximum length of the body summary of a PlannedChange.
+const maxBodySummary = 256
+
+type dryRunContextKey struct{}
+
+// WithDryRun returns a copy of ctx in which mutating requests (POST, PUT,
+// PATCH and DELETE) are not sent to Cloud Connector but recorded in the
+// returned Plan, and answered with an empty 204 No Content response. Reading
+// requests are still sent, so orchestration methods see the current state.
+// The plan can be rendered for approval before running the same calls again
+// without dry-run.
+func WithDryRun(ctx context.Context) (context.Context, *Plan) {
+	plan := new(Plan)
+	return context.WithValue(ctx, dryRunContextKey{}, plan), plan
+}
+
+// dryRunPlan returns the Plan of a dry-run context, or nil.
+func dryRunPlan(ctx context.Context) *Plan {
+	plan, _ := ctx.Value(dryRunContextKey{}).(*Plan)
+	return plan
+}
+
+// A Plan collects the changes a dry run would have made. It is safe for
+// concurrent use.
+type Plan struct {
+	mu      sync.Mutex
+	changes []PlannedChange
+}
+
+// PlannedChange is a mutating request recorded during a dry run.
+type PlannedChange struct {
+	Method string
+	Path   string
+	Body   string // summary of the request body with secrets redacted
+}
+
+func (c PlannedChange) String() string {
+	if c.Body == "" {
+		return c.Method + " " + c.Path
+	}
+	return c.Method + " " + c.Path + " " + c.Body
+}
+
+// Changes returns the changes recorded so far, in the order they were made.
+func (p *Plan) Changes() []PlannedChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedChange(nil), p.changes...)
+}
+
+func (p *Plan) record(req *http.Request) (*http.Response, error) {
+	change := PlannedChange{Method: req.Method, Path: req.URL.Path}
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case req.GetBody != nil:
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+		change.Body = summarizeBody(redactBody(data))
+	default:
+		change.Body = fmt.Sprintf("<%d bytes of %v>", req.ContentLength, req.Header.Get("Content-Type"))
+	}
+
+	p.mu.Lock()
+	p.changes = append(p.changes, change)
+	p.mu.Unlock()
+
+	return &http.Response{
+		Status:     "204 No Content",
+		StatusCode: http.StatusNoContent,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func summarizeBody(data []byte) string {
+	s := strings.TrimSpace(string(data))
+	if len(s) > maxBodySummary {
+		s = s[:maxBodySummary] + "..."
+	}
+	return s
+}
+
+// isMutating reports whether method changes state on the server.
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,634 @@
+package scc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type HAService service
+
+// High availability roles of a Cloud Connector instance.
+const (
+	HARoleMaster = "master"
+	HARoleShadow = "shadow"
+)
+
+// High availability states reported for the master and the shadow instance.
+// BINDING and CONNECTING are transient, BROKEN requires intervention.
+const (
+	HAStateConnected    = "CONNECTED"
+	HAStateConnecting   = "CONNECTING"
+	HAStateBinding      = "BINDING"
+	HAStateDisconnected = "DISCONNECTED"
+	HAStateBroken       = "BROKEN"
+)
+
+// Operations that change the high availability state of the master instance.
+const (
+	MasterOpSwitch = "SWITCH" // hand over the master role to the shadow
+	MasterOpReset  = "RESET"  // discard the pairing with the shadow
+)
+
+// Operations that change the high availability state of the shadow instance.
+const (
+	ShadowOpConnect    = "CONNECT"
+	ShadowOpDisconnect = "DISCONNECT"
+)
+
+// ErrHABroken is returned when a high availability state change fails because
+// the instance is in the terminal HAStateBroken state.
+var ErrHABroken = errors.New("high availability state is BROKEN")
+
+// HAState is the high availability state of an instance.
+type HAState struct {
+	State string `json:"state"`
+}
+
+// MasterConfiguration is the high availability configuration of a master
+// instance.
+type MasterConfiguration struct {
+	HAEnabled         bool     `json:"haEnabled"`
+	AllowedShadowHost HostList `json:"allowedShadowHost"`
+}
+
+// HostList is a list of host names. Cloud Connector represents it as a single
+// comma-separated string, which is what it marshals to. It unmarshals from
+// both that form and a JSON array.
+type HostList []string
+
+// MarshalJSON implements the json.Marshaler interface.
+func (l HostList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(l, ","))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *HostList) UnmarshalJSON(data []byte) error {
+	var hosts []string
+	if err := json.Unmarshal(data, &hosts); err == nil {
+		*l = hosts
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = nil
+	for _, host := range strings.Split(s, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			*l = append(*l, host)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether host is in the list, ignoring case.
+func (l HostList) Contains(host string) bool {
+	for _, h := range l {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns the list with host appended, unless it is already contained.
+func (l HostList) Add(host string) HostList {
+	if l.Contains(host) {
+		return l
+	}
+	return append(l[:len(l):len(l)], host)
+}
+
+// Remove returns the list without host.
+func (l HostList) Remove(host string) HostList {
+	var remaining HostList
+	for _, h := range l {
+		if !strings.EqualFold(h, host) {
+			remaining = append(remaining, h)
+		}
+	}
+	return remaining
+}
+
+// GetHASettings gets the high availability role of Cloud Connector, either
+// HARoleMaster or HARoleShadow
+func (s *HAService) GetHASettings(ctx context.Context) (string, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/haRole", nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, resp, err := s.client.DoRaw(ctx, req, nil)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return trimTextBody(data), resp, nil
+}
+
+// SetHASettings sets the high availability role of Cloud Connector, either
+// HARoleMaster or HARoleShadow. The role is sent as plain text, the way
+// GetHASettings receives it.
+func (s *HAService) SetHASettings(ctx context.Context, role string) (*Response, error) {
+	if role != HARoleMaster && role != HARoleShadow {
+		return nil, fmt.Errorf("invalid high availability role %q", role)
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/haRole", role)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// GetMasterConfiguration gets the high availability configuration of the master instance
+func (s *HAService) GetMasterConfiguration(ctx context.Context) (*MasterConfiguration, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/ha/master/config", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := new(MasterConfiguration)
+	resp, err := s.client.Do(ctx, req, config)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return config, resp, nil
+}
+
+// SetMasterConfiguration sets the high availability configuration of the master instance
+func (s *HAService) SetMasterConfiguration(ctx context.Context, config *MasterConfiguration) (*MasterConfiguration, *Response, error) {
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/ha/master/config", config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(MasterConfiguration)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
+
+// AddAllowedShadowHost adds host to the shadow hosts allowed to connect to the master instance
+func (s *HAService) AddAllowedShadowHost(ctx context.Context, host string) (*MasterConfiguration, *Response, error) {
+	return s.updateAllowedShadowHosts(ctx, func(l HostList) HostList { return l.Add(host) })
+}
+
+// RemoveAllowedShadowHost removes host from the shadow hosts allowed to connect to the master instance
+func (s *HAService) RemoveAllowedShadowHost(ctx context.Context, host string) (*MasterConfiguration, *Response, error) {
+	return s.updateAllowedShadowHosts(ctx, func(l HostList) HostList { return l.Remove(host) })
+}
+
+func (s *HAService) updateAllowedShadowHosts(ctx context.Context, update func(HostList) HostList) (*MasterConfiguration, *Response, error) {
+	config, resp, err := s.GetMasterConfiguration(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	config.AllowedShadowHost = update(config.AllowedShadowHost)
+	return s.SetMasterConfiguration(ctx, config)
+}
+
+// GetMasterState gets the high availability state of the master instance
+func (s *HAService) GetMasterState(ctx context.Context) (*HAState, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/ha/master/state", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := new(HAState)
+	resp, err := s.client.Do(ctx, req, state)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return state, resp, nil
+}
+
+// SetMasterState applies a state change operation, such as MasterOpSwitch, to
+// the master instance. If enabled with WithHARetry, the operation is retried
+// while the master is in a transient state.
+func (s *HAService) SetMasterState(ctx context.Context, op string) (*HAState, *Response, error) {
+	return s.changeState(ctx, op, s.client.NewRequest, s.GetMasterState, "api/v1/configuration/connector/ha/master/state")
+}
+
+// ResetMaster resets the high availability state of the master instance,
+// discarding its pairing with the shadow. As resetting underneath a connected
+// shadow leaves the pair inconsistent, ResetMaster refuses to run while the
+// shadow reports HAStateConnected; disconnect the shadow first. The check
+// requires a shadow URL configured with WithShadowURL, without one the reset
+// is applied unchecked.
+func (s *HAService) ResetMaster(ctx context.Context) (*HAState, *Response, error) {
+	if s.client.ShadowURL != nil {
+		shadow, resp, err := s.GetShadowState(ctx)
+		if err != nil {
+			return nil, resp, err
+		}
+		if shadow.State == HAStateConnected {
+			return nil, resp, errors.New("shadow instance is still connected")
+		}
+	}
+
+	return s.SetMasterState(ctx, MasterOpReset)
+}
+
+// GetShadowState gets the high availability state of the shadow instance. It
+// requires a shadow URL configured with WithShadowURL.
+func (s *HAService) GetShadowState(ctx context.Context) (*HAState, *Response, error) {
+	req, err := s.client.NewShadowRequest("GET", "api/v1/configuration/connector/ha/shadow/state", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := new(HAState)
+	resp, err := s.client.Do(ctx, req, state)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return state, resp, nil
+}
+
+// ChangeShadowState applies a state change operation, such as ShadowOpConnect,
+// to the shadow instance. It requires a shadow URL configured with
+// WithShadowURL. If enabled with WithHARetry, the operation is retried while
+// the shadow is in a transient state.
+func (s *HAService) ChangeShadowState(ctx context.Context, op string) (*HAState, *Response, error) {
+	return s.changeState(ctx, op, s.client.NewShadowRequest, s.GetShadowState, "api/v1/configuration/connector/ha/shadow/state")
+}
+
+type haRetryConfig struct {
+	maxAttempts int
+	interval    time.Duration
+}
+
+// WithHARetry makes SetMasterState and ChangeShadowState retry a failed
+// operation up to maxAttempts times in total, waiting interval between
+// attempts, as long as the instance reports a transient state such as
+// HAStateBinding. A terminal HAStateBroken ends the retries with ErrHABroken.
+func WithHARetry(maxAttempts int, interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("invalid number of attempts %d", maxAttempts)
+		}
+		c.haRetry = haRetryConfig{maxAttempts: maxAttempts, interval: interval}
+		return nil
+	}
+}
+
+type requestBuilder func(method, urlStr string, body interface{}) (*http.Request, error)
+
+// changeState posts op to the state endpoint at urlStr, re-polling the state
+// with getState and retrying while it is transient.
+func (s *HAService) changeState(ctx context.Context, op string, newRequest requestBuilder, getState func(context.Context) (*HAState, *Response, error), urlStr string) (*HAState, *Response, error) {
+	maxAttempts := s.client.haRetry.maxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest("POST", urlStr, struct {
+			Op string `json:"op"`
+		}{Op: op})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		state := new(HAState)
+		resp, err := s.client.Do(ctx, req, state)
+		if err == nil {
+			return state, resp, nil
+		}
+		if attempt >= maxAttempts {
+			return nil, resp, err
+		}
+
+		current, _, stateErr := getState(ctx)
+		if stateErr != nil {
+			return nil, resp, err
+		}
+		switch current.State {
+		case HAStateBroken:
+			return current, resp, fmt.Errorf("%w: %v", ErrHABroken, err)
+		case HAStateBinding, HAStateConnecting:
+		default:
+			return nil, resp, err
+		}
+
+		t := time.NewTimer(s.client.haRetry.interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, resp, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// RecoverHAOptions controls which steps RecoverHA may take.
+type RecoverHAOptions struct {
+	// AllowReset permits resetting a BROKEN master with ResetMaster. Without
+	// it, RecoverHA stops and reports the reset as required.
+	AllowReset bool
+}
+
+// HARecoveryReport describes what RecoverHA found and did.
+type HARecoveryReport struct {
+	MasterState string   // master state before recovery
+	ShadowState string   // shadow state before recovery
+	Actions     []string // operations applied, in order
+	ResetNeeded bool     // the master is BROKEN but AllowReset was not set
+}
+
+// RecoverHA inspects the master and shadow states and applies the recovery
+// sequence for a broken high availability pair: a BROKEN or DISCONNECTED
+// shadow is disconnected and connected again, a BROKEN master is reset, after
+// disconnecting the shadow, and the shadow is then rebound. A healthy pair is
+// left untouched. It requires a shadow URL configured with WithShadowURL.
+// opts may be nil, in which case the master is never reset.
+func (s *HAService) RecoverHA(ctx context.Context, opts *RecoverHAOptions) (*HARecoveryReport, error) {
+	if s.client.ShadowURL == nil {
+		return nil, ErrNoShadowURL
+	}
+	if opts == nil {
+		opts = &RecoverHAOptions{}
+	}
+
+	master, _, err := s.GetMasterState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shadow, _, err := s.GetShadowState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &HARecoveryReport{MasterState: master.State, ShadowState: shadow.State}
+
+	apply := func(action string, fn func(context.Context, string) (*HAState, *Response, error), op string) error {
+		if _, _, err := fn(ctx, op); err != nil {
+			return fmt.Errorf("%v: %w", action, err)
+		}
+		report.Actions = append(report.Actions, action)
+		return nil
+	}
+
+	if master.State == HAStateBroken {
+		if !opts.AllowReset {
+			report.ResetNeeded = true
+			return report, nil
+		}
+		if shadow.State != HAStateDisconnected {
+			if err := apply("disconnect shadow", s.ChangeShadowState, ShadowOpDisconnect); err != nil {
+				return report, err
+			}
+		}
+		if _, _, err := s.ResetMaster(ctx); err != nil {
+			return report, fmt.Errorf("reset master: %w", err)
+		}
+		report.Actions = append(report.Actions, "reset master")
+		return report, apply("connect shadow", s.ChangeShadowState, ShadowOpConnect)
+	}
+
+	switch shadow.State {
+	case HAStateBroken:
+		if err := apply("disconnect shadow", s.ChangeShadowState, ShadowOpDisconnect); err != nil {
+			return report, err
+		}
+		fallthrough
+	case HAStateDisconnected:
+		return report, apply("connect shadow", s.ChangeShadowState, ShadowOpConnect)
+	}
+
+	return report, nil
+}
+
+// VerifyHAPair reads the configuration of the master and, using the shadow
+// URL configured with WithShadowURL, of the shadow instance and reports the
+// inconsistencies between them that commonly make a failover fail. An empty
+// result means no inconsistency was found. It changes nothing.
+func (s *HAService) VerifyHAPair(ctx context.Context) ([]string, error) {
+	if s.client.ShadowURL == nil {
+		return nil, ErrNoShadowURL
+	}
+
+	var master *MasterConfiguration
+	var shadow *ShadowConfiguration
+	g := new(group)
+	g.Go(func() (err error) {
+		master, _, err = s.GetMasterConfiguration(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		shadow, _, err = s.GetShadowConfiguration(ctx)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	problems := []string{}
+	if !master.HAEnabled {
+		problems = append(problems, "high availability is disabled on the master")
+	}
+	if len(master.AllowedShadowHost) > 0 && !master.AllowedShadowHost.Contains(shadow.OwnHost) {
+		problems = append(problems, fmt.Sprintf("shadow host %q is not among the hosts allowed by the master %v", shadow.OwnHost, []string(master.AllowedShadowHost)))
+	}
+	if host := s.client.BaseURL.Hostname(); !strings.EqualFold(shadow.MasterHost, host) {
+		problems = append(problems, fmt.Sprintf("shadow expects master host %q, but the master is reached at %q", shadow.MasterHost, host))
+	}
+	if shadow.TakeoverDelayInSeconds < shadow.CheckIntervalInSeconds {
+		problems = append(problems, fmt.Sprintf("takeover delay of %ds is shorter than the check interval of %ds", shadow.TakeoverDelayInSeconds, shadow.CheckIntervalInSeconds))
+	}
+	if shadow.RequestTimeoutInMillis < shadow.ConnectTimeoutInMillis {
+		problems = append(problems, fmt.Sprintf("request timeout of %dms is shorter than the connect timeout of %dms", shadow.RequestTimeoutInMillis, shadow.ConnectTimeoutInMillis))
+	}
+	return problems, nil
+}
+
+// ShadowConfiguration is the high availability configuration of a shadow
+// instance.
+type ShadowConfiguration struct {
+	MasterHost             string `json:"masterHost"`
+	MasterPort             string `json:"masterPort"`
+	OwnHost                string `json:"ownHost"`
+	CheckIntervalInSeconds int    `json:"checkIntervalInSeconds"`
+	TakeoverDelayInSeconds int    `json:"takeoverDelayInSeconds"`
+	ConnectTimeoutInMillis int    `json:"connectTimeoutInMillis"`
+	RequestTimeoutInMillis int    `json:"requestTimeoutInMillis"`
+}
+
+// Recommended shadow settings, used by DefaultShadowConfiguration.
+const (
+	DefaultCheckIntervalInSeconds = 30
+	DefaultTakeoverDelayInSeconds = 30
+	DefaultConnectTimeoutInMillis = 20000
+	DefaultRequestTimeoutInMillis = 30000
+)
+
+// DefaultShadowConfiguration returns a shadow configuration for the given
+// hosts with the recommended check interval, takeover delay and timeouts.
+func DefaultShadowConfiguration(masterHost, masterPort, ownHost string) ShadowConfiguration {
+	return ShadowConfiguration{
+		MasterHost:             masterHost,
+		MasterPort:             masterPort,
+		OwnHost:                ownHost,
+		CheckIntervalInSeconds: DefaultCheckIntervalInSeconds,
+		TakeoverDelayInSeconds: DefaultTakeoverDelayInSeconds,
+		ConnectTimeoutInMillis: DefaultConnectTimeoutInMillis,
+		RequestTimeoutInMillis: DefaultRequestTimeoutInMillis,
+	}
+}
+
+// GetShadowConfiguration gets the high availability configuration of the
+// shadow instance. It requires a shadow URL configured with WithShadowURL.
+func (s *HAService) GetShadowConfiguration(ctx context.Context) (*ShadowConfiguration, *Response, error) {
+	req, err := s.client.NewShadowRequest("GET", "api/v1/configuration/connector/ha/shadow/config", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := new(ShadowConfiguration)
+	resp, err := s.client.Do(ctx, req, config)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return config, resp, nil
+}
+
+// SetShadowConfiguration sets the high availability configuration of the
+// shadow instance. It requires a shadow URL configured with WithShadowURL.
+// SetShadowConfigurationStruct is less error-prone to call.
+func (s *HAService) SetShadowConfiguration(ctx context.Context, masterHost, masterPort, ownHost string, checkIntervalInSeconds, takeoverDelayInSeconds, connectTimeoutInMillis, requestTimeoutInMillis int) (*ShadowConfiguration, *Response, error) {
+	return s.SetShadowConfigurationStruct(ctx, ShadowConfiguration{
+		MasterHost:             masterHost,
+		MasterPort:             masterPort,
+		OwnHost:                ownHost,
+		CheckIntervalInSeconds: checkIntervalInSeconds,
+		TakeoverDelayInSeconds: takeoverDelayInSeconds,
+		ConnectTimeoutInMillis: connectTimeoutInMillis,
+		RequestTimeoutInMillis: requestTimeoutInMillis,
+	})
+}
+
+// ErrConfigNotApplied is returned when the connector accepted a configuration
+// but the configuration read back differs from the requested one.
+var ErrConfigNotApplied = errors.New("configuration not applied as requested")
+
+// WithShadowConfigVerification makes SetShadowConfiguration and
+// SetShadowConfigurationStruct read the shadow configuration back after
+// setting it and fail with ErrConfigNotApplied if the effective values differ
+// from the requested ones, e.g. because the connector clamped a timeout. This
+// costs an extra GET per call and is disabled by default.
+func WithShadowConfigVerification() ClientOption {
+	return func(c *Client) error {
+		c.verifyShadowConfig = true
+		return nil
+	}
+}
+
+// SetShadowConfigurationStruct sets the high availability configuration of
+// the shadow instance, see DefaultShadowConfiguration for recommended values.
+// It requires a shadow URL configured with WithShadowURL.
+func (s *HAService) SetShadowConfigurationStruct(ctx context.Context, config ShadowConfiguration) (*ShadowConfiguration, *Response, error) {
+	req, err := s.client.NewShadowRequest("PUT", "api/v1/configuration/connector/ha/shadow/config", config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(ShadowConfiguration)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if s.client.verifyShadowConfig {
+		effective, resp, err := s.GetShadowConfiguration(ctx)
+		if err != nil {
+			return nil, resp, err
+		}
+		if *effective != config {
+			return effective, resp, fmt.Errorf("%w: requested %+v, effective %+v", ErrConfigNotApplied, config, *effective)
+		}
+		return effective, resp, nil
+	}
+
+	return updated, resp, nil
+}
+
+// pausedCheckIntervalInSeconds is the check interval and takeover delay set by
+// PauseHAChecks, long enough to rule out a takeover during maintenance.
+const pausedCheckIntervalInSeconds = 24 * 60 * 60
+
+// ErrHAChecksPaused is returned by PauseHAChecks when the checks of the shadow
+// instance were already paused through the same client.
+var ErrHAChecksPaused = errors.New("high availability checks are already paused")
+
+// ErrHAChecksNotPaused is returned by ResumeHAChecks when the checks of the
+// shadow instance were not paused through the same client.
+var ErrHAChecksNotPaused = errors.New("high availability checks are not paused")
+
+// PauseHAChecks keeps the shadow instance from taking over during planned
+// maintenance by raising its check interval and takeover delay to a day. The
+// configuration before the pause is kept by the client and returned;
+// ResumeHAChecks restores it afterwards. If the checks were already paused
+// through this client it returns ErrHAChecksPaused. It requires a shadow URL
+// configured with WithShadowURL.
+func (s *HAService) PauseHAChecks(ctx context.Context) (*ShadowConfiguration, *Response, error) {
+	s.client.haPauseMu.Lock()
+	defer s.client.haPauseMu.Unlock()
+
+	if s.client.haPaused != nil {
+		return nil, nil, ErrHAChecksPaused
+	}
+
+	prior, resp, err := s.GetShadowConfiguration(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	paused := *prior
+	paused.CheckIntervalInSeconds = pausedCheckIntervalInSeconds
+	paused.TakeoverDelayInSeconds = pausedCheckIntervalInSeconds
+	_, resp, err = s.SetShadowConfigurationStruct(ctx, paused)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	saved := *prior
+	s.client.haPaused = &saved
+	return prior, resp, nil
+}
+
+// ResumeHAChecks restores the shadow configuration saved by PauseHAChecks
+// and returns the restored configuration. If the checks were not paused
+// through this client it returns ErrHAChecksNotPaused. When the restore
+// fails the checks stay paused, so ResumeHAChecks can be called again. It
+// requires a shadow URL configured with WithShadowURL.
+func (s *HAService) ResumeHAChecks(ctx context.Context) (*ShadowConfiguration, *Response, error) {
+	s.client.haPauseMu.Lock()
+	defer s.client.haPauseMu.Unlock()
+
+	if s.client.haPaused == nil {
+		return nil, nil, ErrHAChecksNotPaused
+	}
+
+	restored, resp, err := s.SetShadowConfigurationStruct(ctx, *s.client.haPaused)
+	if err != nil {
+		return restored, resp, err
+	}
+
+	s.client.haPaused = nil
+	return restored, resp, nil
+}
@@ -0,0 +1,84 @@
+package scc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestHAService_SetHASettings(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/api/v1/configuration/connector/haRole", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("Request method = %v, want PUT", r.Method)
+		}
+		if got, want := r.Header.Get("Content-Type"), "text/plain; charset=utf-8"; got != want {
+			t.Errorf("Content-Type = %q, want %q", got, want)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), "master"; got != want {
+			t.Errorf("Request body = %q, want %q", got, want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.HA.SetHASettings(context.Background(), HARoleMaster); err != nil {
+		t.Fatalf("SetHASettings returned error: %v", err)
+	}
+}
+
+func TestHAService_PauseResumeHAChecks(t *testing.T) {
+	client, mux := setup(t)
+	client.ShadowURL = client.BaseURL
+
+	// The prior configuration already uses the interval PauseHAChecks sets,
+	// which must not be mistaken for a pause.
+	prior := ShadowConfiguration{
+		MasterHost:             "master.example.com",
+		MasterPort:             "8443",
+		CheckIntervalInSeconds: pausedCheckIntervalInSeconds,
+		TakeoverDelayInSeconds: 60,
+	}
+	current := prior
+	mux.HandleFunc("/api/v1/configuration/connector/ha/shadow/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			if err := json.NewDecoder(r.Body).Decode(&current); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+		}
+		json.NewEncoder(w).Encode(current)
+	})
+
+	ctx := context.Background()
+	if _, _, err := client.HA.ResumeHAChecks(ctx); !errors.Is(err, ErrHAChecksNotPaused) {
+		t.Fatalf("ResumeHAChecks before pause returned error %v, want ErrHAChecksNotPaused", err)
+	}
+
+	got, _, err := client.HA.PauseHAChecks(ctx)
+	if err != nil {
+		t.Fatalf("PauseHAChecks returned error: %v", err)
+	}
+	if *got != prior {
+		t.Errorf("PauseHAChecks returned %+v, want %+v", *got, prior)
+	}
+	if current.TakeoverDelayInSeconds != pausedCheckIntervalInSeconds {
+		t.Errorf("takeover delay while paused = %d, want %d", current.TakeoverDelayInSeconds, pausedCheckIntervalInSeconds)
+	}
+
+	if _, _, err := client.HA.PauseHAChecks(ctx); !errors.Is(err, ErrHAChecksPaused) {
+		t.Errorf("second PauseHAChecks returned error %v, want ErrHAChecksPaused", err)
+	}
+
+	if _, _, err := client.HA.ResumeHAChecks(ctx); err != nil {
+		t.Fatalf("ResumeHAChecks returned error: %v", err)
+	}
+	if current != prior {
+		t.Errorf("configuration after resume = %+v, want %+v", current, prior)
+	}
+	if _, _, err := client.HA.ResumeHAChecks(ctx); !errors.Is(err, ErrHAChecksNotPaused) {
+		t.Errorf("second ResumeHAChecks returned error %v, want ErrHAChecksNotPaused", err)
+	}
+}
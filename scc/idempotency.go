@@ -0,0 +1,22 @@
+package scc
+
+import "context"
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying key as the idempotency key
+// for the create operation it is passed to, e.g. AddSubaccount, which sends it
+// as Idempotency-Key header. Cloud Connector itself ignores the header; it is
+// only useful with proxies in front of the connector that honor it. Callers
+// that retry an operation themselves should reuse the same key for every try.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the idempotency key stored in ctx, if any.
+func idempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
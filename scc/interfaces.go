@@ -0,0 +1,242 @@
+package scc
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// The interfaces below list the methods of the services of a Client. Code
+// that depends on a service can accept the interface instead of the concrete
+// type, so that it can be tested with a fake instead of a live connector.
+
+// CommonClient is implemented by *CommonService.
+type CommonClient interface {
+	DetectVersionChange(ctx context.Context, knownVersion string) (bool, string, *Response, error)
+	GetAuditLogRetention(ctx context.Context) (*AuditLogRetention, *Response, error)
+	GetBackendTimeouts(ctx context.Context) (*BackendTimeouts, *Response, error)
+	GetCapabilities(ctx context.Context) (*Capabilities, *Response, error)
+	GetCommonProperties(ctx context.Context) (*CommonProperties, *Response, error)
+	GetConnectorTime(ctx context.Context) (*ConnectorTime, *Response, error)
+	GetDescriptionMetadata(ctx context.Context) (map[string]string, *Response, error)
+	GetLastRestart(ctx context.Context) (*RestartInfo, *Response, error)
+	GetLicenseInfo(ctx context.Context) (*LicenseInfo, *Response, error)
+	GetOutboundBinding(ctx context.Context) (*OutboundBinding, *Response, error)
+	GetTLSPolicy(ctx context.Context) (*TLSPolicy, *Response, error)
+	GetTunnelCompression(ctx context.Context) (*TunnelCompression, *Response, error)
+	GetUpdatePolicy(ctx context.Context) (*UpdatePolicy, *Response, error)
+	GetVersion(ctx context.Context) (*Version, *Response, error)
+	SetAuditLogRetention(ctx context.Context, retention *AuditLogRetention) (*AuditLogRetention, *Response, error)
+	SetBackendTimeouts(ctx context.Context, timeouts *BackendTimeouts) (*BackendTimeouts, *Response, error)
+	SetDescription(ctx context.Context, description string) (*CommonProperties, *Response, error)
+	SetDescriptionMetadata(ctx context.Context, metadata map[string]string) (*CommonProperties, *Response, error)
+	SetOutboundBinding(ctx context.Context, binding *OutboundBinding) (*OutboundBinding, *Response, error)
+	SetTLSPolicy(ctx context.Context, policy *TLSPolicy) (*TLSPolicy, *Response, error)
+	SetTunnelCompression(ctx context.Context, compression *TunnelCompression) (*TunnelCompression, *Response, error)
+	SetUpdatePolicy(ctx context.Context, policy *UpdatePolicy) (*UpdatePolicy, *Response, error)
+	ValidateConfiguration(ctx context.Context) ([]*ConfigurationFinding, *Response, error)
+}
+
+// BackupClient is implemented by *BackupService.
+type BackupClient interface {
+	CreateBackup(ctx context.Context, password string, file *os.File) (*Response, error)
+	GetBackupInfo(ctx context.Context) (*BackupInfo, *Response, error)
+	GetBackupSchedule(ctx context.Context) (*BackupSchedule, *Response, error)
+	RestoreBackup(ctx context.Context, password string, file *os.File) (*Response, error)
+	RestoreBackupWithRollback(ctx context.Context, password string, file *os.File) (*RestoreRollback, *Response, error)
+	SetBackupSchedule(ctx context.Context, schedule *BackupSchedule) (*BackupSchedule, *Response, error)
+}
+
+// SubaccountClient is implemented by *SubaccountService.
+type SubaccountClient interface {
+	AddSubaccount(ctx context.Context, sa *Subaccount) (*Subaccount, *Response, error)
+	ConnectSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error)
+	DeleteSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error)
+	DeleteSubaccounts(ctx context.Context, keys []SubaccountKey, opts *DeleteSubaccountsOptions) (map[SubaccountKey]error, error)
+	DisconnectSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error)
+	EnsureSubaccountConnected(ctx context.Context, regionHost, subaccount string, opts *ReconnectOptions) (string, int, error)
+	GetAuditLevel(ctx context.Context, regionHost, subaccount string) (*AuditLevel, *Response, error)
+	GetSubaccount(ctx context.Context, regionHost, subaccount string) (*Subaccount, *Response, error)
+	ListApplicationConnections(ctx context.Context, regionHost, subaccount string) ([]*ApplicationConnection, *Response, error)
+	ListAuditLevels(ctx context.Context, expected string) (*AuditLevelReport, error)
+	ListConnectedSubaccounts(ctx context.Context) ([]SubaccountKey, error)
+	ListDisconnectedSubaccounts(ctx context.Context) ([]SubaccountKey, error)
+	ListSubaccountStates(ctx context.Context) ([]*SubaccountState, error)
+	ListSubaccounts(ctx context.Context) ([]*Subaccount, *Response, error)
+	SetAuditLevel(ctx context.Context, regionHost, subaccount, level string) (*Response, error)
+	SetAuditLevelAll(ctx context.Context, level string) (map[SubaccountKey]error, error)
+	SubaccountExists(ctx context.Context, regionHost, subaccount string) (bool, *Response, error)
+	TestRegionReachability(ctx context.Context, regionHost string) (*RegionReachability, *Response, error)
+	UpdateSubaccount(ctx context.Context, regionHost, subaccount string, sa *Subaccount) (*Subaccount, *Response, error)
+}
+
+// MonitoringClient is implemented by *MonitoringService.
+type MonitoringClient interface {
+	ExportConnectionsCSV(ctx context.Context, w io.Writer) (*Response, error)
+	ExportPerformanceCSV(ctx context.Context, w io.Writer) (*Response, error)
+	GetOpenConnections(ctx context.Context, filter *ConnectionFilter) ([]*Connection, *Response, error)
+	GetPerformance(ctx context.Context) ([]*BackendPerformance, *Response, error)
+	GetQueueMetrics(ctx context.Context) (*QueueMetrics, *Response, error)
+	GetRecentRequests(ctx context.Context, regionHost, subaccount string, limit int) ([]*RecentRequest, *Response, error)
+	GetResourceStats(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) ([]*ResourceStats, *Response, error)
+	GetTunnelLatency(ctx context.Context, regionHost, subaccount string) (*TunnelLatency, *Response, error)
+	ResetMonitoringStatistics(ctx context.Context) (*Response, error)
+	StreamOpenConnections(ctx context.Context, fn func(Connection) error) (*Response, error)
+}
+
+// SystemMappingClient is implemented by *SystemMappingService.
+type SystemMappingClient interface {
+	CreateSystemMapping(ctx context.Context, regionHost, subaccount string, mapping *SystemMapping) (*Response, error)
+	CreateSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, resource *SystemMappingResource) (*Response, error)
+	DeleteSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*Response, error)
+	DeleteSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string) (*Response, error)
+	GetEffectiveSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SystemMapping, *Response, error)
+	GetMappingTLSInfo(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*MappingTLSInfo, *Response, error)
+	GetResourceAllowedMethods(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string) ([]string, *Response, error)
+	GetSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SystemMapping, *Response, error)
+	GetSystemMappingSNC(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SNCSettings, *Response, error)
+	ListExposedHosts(ctx context.Context, regionHost, subaccount string) ([]*ExposedHost, *Response, error)
+	ListSystemMappingResources(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) ([]*SystemMappingResource, *Response, error)
+	ListSystemMappings(ctx context.Context, regionHost, subaccount string) ([]*SystemMapping, *Response, error)
+	SetAllMappingsEnabled(ctx context.Context, regionHost, subaccount string, enabled bool) (map[string]error, error)
+	SetMappingDescription(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, description string) (*Response, error)
+	SetResourceAllowedMethods(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string, methods []string) ([]string, *Response, error)
+	SetSystemMappingSNC(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, settings SNCSettings) (*Response, error)
+	SystemMappingExists(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (bool, *Response, error)
+	UpdateSystemMapping(ctx context.Context, regionHost, subaccount string, mapping *SystemMapping) (*Response, error)
+	UpdateSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, resource *SystemMappingResource) (*Response, error)
+}
+
+// ServiceChannelClient is implemented by *ServiceChannelService.
+type ServiceChannelClient interface {
+	CreateServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, channel *ServiceChannel) (*Response, error)
+	DeleteServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int) (*Response, error)
+	GetServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int) (*ServiceChannel, *Response, error)
+	ListAllServiceChannels(ctx context.Context, regionHost, subaccount string) (map[ChannelType][]*ServiceChannel, error)
+	ListServiceChannels(ctx context.Context, regionHost, subaccount string, channelType ChannelType) ([]*ServiceChannel, *Response, error)
+	SetChannelConnections(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id, count int) (*ServiceChannel, *Response, error)
+	SetServiceChannelEnabled(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int, enabled bool) (*ServiceChannel, *Response, error)
+	UpdateServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, channel *ServiceChannel) (*Response, error)
+}
+
+// HAClient is implemented by *HAService.
+type HAClient interface {
+	AddAllowedShadowHost(ctx context.Context, host string) (*MasterConfiguration, *Response, error)
+	ChangeShadowState(ctx context.Context, op string) (*HAState, *Response, error)
+	GetHASettings(ctx context.Context) (string, *Response, error)
+	GetMasterConfiguration(ctx context.Context) (*MasterConfiguration, *Response, error)
+	GetMasterState(ctx context.Context) (*HAState, *Response, error)
+	GetShadowConfigSummary(ctx context.Context) (*ShadowConfigSummary, error)
+	GetShadowConfiguration(ctx context.Context) (*ShadowConfiguration, *Response, error)
+	GetShadowState(ctx context.Context) (*HAState, *Response, error)
+	PauseHAChecks(ctx context.Context) (*ShadowConfiguration, *Response, error)
+	RecoverHA(ctx context.Context, opts *RecoverHAOptions) (*HARecoveryReport, error)
+	RemoveAllowedShadowHost(ctx context.Context, host string) (*MasterConfiguration, *Response, error)
+	ResetMaster(ctx context.Context) (*HAState, *Response, error)
+	ResumeHAChecks(ctx context.Context) (*ShadowConfiguration, *Response, error)
+	SetHASettings(ctx context.Context, role string) (*Response, error)
+	SetMasterConfiguration(ctx context.Context, config *MasterConfiguration) (*MasterConfiguration, *Response, error)
+	SetMasterState(ctx context.Context, op string) (*HAState, *Response, error)
+	SetShadowConfiguration(ctx context.Context, masterHost, masterPort, ownHost string, checkIntervalInSeconds, takeoverDelayInSeconds, connectTimeoutInMillis, requestTimeoutInMillis int) (*ShadowConfiguration, *Response, error)
+	SetShadowConfigurationStruct(ctx context.Context, config ShadowConfiguration) (*ShadowConfiguration, *Response, error)
+	VerifyHAPair(ctx context.Context) ([]string, error)
+}
+
+// AuthenticationClient is implemented by *AuthenticationService.
+type AuthenticationClient interface {
+	AddLDAPServer(ctx context.Context, server *LDAPServer) (*LDAPServer, *Response, error)
+	ChangeAdminPassword(ctx context.Context, oldPassword, newPassword string) (*Response, error)
+	DeleteLDAPServer(ctx context.Context, id string) (*Response, error)
+	ListLDAPServers(ctx context.Context) ([]*LDAPServer, *Response, error)
+	ListSessions(ctx context.Context) ([]*Session, *Response, error)
+	RevokeSession(ctx context.Context, id string) (*Response, error)
+	UpdateLDAPServer(ctx context.Context, server *LDAPServer) (*Response, error)
+}
+
+// PrincipalPropagationClient is implemented by *PrincipalPropagationService.
+type PrincipalPropagationClient interface {
+	AddSubaccountTrustedCA(ctx context.Context, regionHost, subaccount string, certPEM []byte) (*TrustedCA, *Response, error)
+	AddSubaccountTrustedCABundle(ctx context.Context, regionHost, subaccount string, bundle []byte) ([]*TrustedCAResult, error)
+	AddTrustedCA(ctx context.Context, certPEM []byte) (*TrustedCA, *Response, error)
+	AddTrustedCABundle(ctx context.Context, pemBundle []byte) ([]*TrustedCAResult, error)
+	DeleteSubaccountTrustedCA(ctx context.Context, regionHost, subaccount, fingerprint string) (*Response, error)
+	ListPrincipalPropagationSessions(ctx context.Context) ([]*PrincipalPropagationSession, *Response, error)
+	ListSubaccountTrustedCAs(ctx context.Context, regionHost, subaccount string) ([]*TrustedCA, *Response, error)
+	ListTrustedCAs(ctx context.Context) ([]*TrustedCA, *Response, error)
+	TestPrincipalPropagation(ctx context.Context, regionHost, subaccount, sampleUser string) (*PrincipalPropagationTestResult, *Response, error)
+}
+
+// LogClient is implemented by *LogService.
+type LogClient interface {
+	DownloadLogs(ctx context.Context, w io.Writer) (*Response, error)
+	FollowLog(ctx context.Context, lines int, w io.Writer, interval time.Duration) error
+	GetLogDiskUsage(ctx context.Context) (*LogDiskUsage, *Response, error)
+	GetLoggingConfiguration(ctx context.Context) (*LoggingConfiguration, *Response, error)
+	GetTraceLevel(ctx context.Context) (LogLevel, *Response, error)
+	SetCategoryLogLevels(ctx context.Context, levels map[string]LogLevel) (*LoggingConfiguration, *Response, error)
+	SetTraceLevel(ctx context.Context, level LogLevel) (*Response, error)
+	TailLog(ctx context.Context, lines int, w io.Writer) error
+}
+
+// CertificateClient is implemented by *CertificateService.
+type CertificateClient interface {
+	GenerateCSR(ctx context.Context, subject string, sans []string) ([]byte, *Response, error)
+	GetTunnelCertificateChain(ctx context.Context) ([]*Certificate, *Response, error)
+	ListAllCertificates(ctx context.Context) ([]*ManagedCertificate, error)
+}
+
+var (
+	_ CommonClient               = (*CommonService)(nil)
+	_ BackupClient               = (*BackupService)(nil)
+	_ SubaccountClient           = (*SubaccountService)(nil)
+	_ MonitoringClient           = (*MonitoringService)(nil)
+	_ SystemMappingClient        = (*SystemMappingService)(nil)
+	_ ServiceChannelClient       = (*ServiceChannelService)(nil)
+	_ HAClient                   = (*HAService)(nil)
+	_ AuthenticationClient       = (*AuthenticationService)(nil)
+	_ PrincipalPropagationClient = (*PrincipalPropagationService)(nil)
+	_ LogClient                  = (*LogService)(nil)
+	_ CertificateClient          = (*CertificateService)(nil)
+)
+
+// The service fields of Client keep their concrete types, so that existing
+// code using them keeps compiling and the Client's own helpers, such as
+// ExportConfig, always talk to a live connector. The accessors below return
+// the services as interfaces for code that should only depend on those.
+
+// CommonClient returns c.Common as a CommonClient.
+func (c *Client) CommonClient() CommonClient { return c.Common }
+
+// BackupClient returns c.Backup as a BackupClient.
+func (c *Client) BackupClient() BackupClient { return c.Backup }
+
+// SubaccountClient returns c.Subaccount as a SubaccountClient.
+func (c *Client) SubaccountClient() SubaccountClient { return c.Subaccount }
+
+// MonitoringClient returns c.Monitoring as a MonitoringClient.
+func (c *Client) MonitoringClient() MonitoringClient { return c.Monitoring }
+
+// SystemMappingClient returns c.SystemMapping as a SystemMappingClient.
+func (c *Client) SystemMappingClient() SystemMappingClient { return c.SystemMapping }
+
+// ServiceChannelClient returns c.ServiceChannel as a ServiceChannelClient.
+func (c *Client) ServiceChannelClient() ServiceChannelClient { return c.ServiceChannel }
+
+// HAClient returns c.HA as an HAClient.
+func (c *Client) HAClient() HAClient { return c.HA }
+
+// AuthenticationClient returns c.Authentication as an AuthenticationClient.
+func (c *Client) AuthenticationClient() AuthenticationClient { return c.Authentication }
+
+// PrincipalPropagationClient returns c.PrincipalPropagation as a
+// PrincipalPropagationClient.
+func (c *Client) PrincipalPropagationClient() PrincipalPropagationClient {
+	return c.PrincipalPropagation
+}
+
+// LogClient returns c.Log as a LogClient.
+func (c *Client) LogClient() LogClient { return c.Log }
+
+// CertificateClient returns c.Certificate as a CertificateClient.
+func (c *Client) CertificateClient() CertificateClient { return c.Certificate }
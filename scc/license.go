@@ -0,0 +1,32 @@
+package scc
+
+import (
+	"context"
+)
+
+// LicenseInfo describes the edition of Cloud Connector and its license.
+type LicenseInfo struct {
+	Edition  string     `json:"edition"`
+	Features []string   `json:"features,omitempty"`
+	Expiry   *Timestamp `json:"expiry,omitempty"` // nil if the license does not expire
+}
+
+// GetLicenseInfo gets the edition and license information of Cloud Connector.
+// It returns ErrNotSupported if the connector does not report it.
+func (s *CommonService) GetLicenseInfo(ctx context.Context) (*LicenseInfo, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/connector/license", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := new(LicenseInfo)
+	resp, err := s.client.Do(ctx, req, info)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return info, resp, nil
+}
@@ -0,0 +1,85 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// LoggingConfiguration is the logging configuration of Cloud Connector.
+type LoggingConfiguration struct {
+	// Level is the global trace level, see GetTraceLevel.
+	Level LogLevel `json:"level"`
+
+	// Categories holds the log level of individual log categories, such as
+	// the tunnel, overriding Level for them.
+	Categories map[string]LogLevel `json:"categories"`
+
+	// Destinations lists where log entries are written, e.g. "FILE".
+	Destinations []string `json:"destinations"`
+
+	Rotation *LogRotation `json:"rotation,omitempty"`
+}
+
+// LogRotation is the rotation setting of the log files.
+type LogRotation struct {
+	MaxFileSizeInMB int `json:"maxFileSizeInMB"` // size at which a file is rotated
+	MaxFiles        int `json:"maxFiles"`        // rotated files kept
+}
+
+// GetLoggingConfiguration gets the logging configuration of Cloud Connector,
+// including the log level of each category. It returns ErrNotSupported if the
+// connector only offers the global trace level.
+func (s *LogService) GetLoggingConfiguration(ctx context.Context) (*LoggingConfiguration, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/logging", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := new(LoggingConfiguration)
+	resp, err := s.client.Do(ctx, req, config)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if config.Categories == nil {
+		config.Categories = map[string]LogLevel{}
+	}
+	return config, resp, nil
+}
+
+// SetCategoryLogLevels sets the log level of the given categories, e.g. only
+// the tunnel category to LogLevelDebug, leaving other categories unchanged.
+// It returns the effective logging configuration, or ErrNotSupported if the
+// connector only offers the global trace level.
+func (s *LogService) SetCategoryLogLevels(ctx context.Context, levels map[string]LogLevel) (*LoggingConfiguration, *Response, error) {
+	if len(levels) == 0 {
+		return nil, nil, errors.New("no log categories given")
+	}
+	for category, level := range levels {
+		if category == "" {
+			return nil, nil, errors.New("log category must not be empty")
+		}
+		if !level.Valid() {
+			return nil, nil, fmt.Errorf("invalid log level %q for category %v", level, category)
+		}
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/logging/categories", levels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return s.GetLoggingConfiguration(ctx)
+}
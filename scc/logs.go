@@ -0,0 +1,209 @@
+package scc
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+type LogService service
+
+// traceLogFile is the name of the main log file within the log archive.
+const traceLogFile = "ljs_trace.log"
+
+// LogLevel is the level of detail Cloud Connector logs at.
+type LogLevel string
+
+// Log levels, from least to most detailed.
+const (
+	LogLevelError   LogLevel = "ERROR"
+	LogLevelWarning LogLevel = "WARNING"
+	LogLevelInfo    LogLevel = "INFO"
+	LogLevelDebug   LogLevel = "DEBUG"
+	LogLevelAll     LogLevel = "ALL"
+)
+
+// Valid reports whether l is a log level supported by Cloud Connector.
+func (l LogLevel) Valid() bool {
+	switch l {
+	case LogLevelError, LogLevelWarning, LogLevelInfo, LogLevelDebug, LogLevelAll:
+		return true
+	}
+	return false
+}
+
+// LogDiskUsage is the disk space used by log and trace files and the space
+// still available to them.
+type LogDiskUsage struct {
+	UsedBytes int64 `json:"usedBytes"`
+	FreeBytes int64 `json:"freeBytes"`
+}
+
+// GetTraceLevel gets the trace level of Cloud Connector
+func (s *LogService) GetTraceLevel(ctx context.Context) (LogLevel, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/trace", nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var trace struct {
+		Level LogLevel `json:"level"`
+	}
+	resp, err := s.client.Do(ctx, req, &trace)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return trace.Level, resp, nil
+}
+
+// SetTraceLevel sets the trace level of Cloud Connector
+func (s *LogService) SetTraceLevel(ctx context.Context, level LogLevel) (*Response, error) {
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/trace", struct {
+		Level LogLevel `json:"level"`
+	}{Level: level})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// GetLogDiskUsage gets the disk space used by log and trace files. Together
+// with SetTraceLevel it allows lowering the trace level before the disk fills
+// up. It returns ErrNotSupported if the connector does not report disk usage.
+func (s *LogService) GetLogDiskUsage(ctx context.Context) (*LogDiskUsage, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/logs/diskUsage", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usage := new(LogDiskUsage)
+	resp, err := s.client.Do(ctx, req, usage)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return usage, resp, nil
+}
+
+// DownloadLogs downloads a ZIP archive with the log files of Cloud Connector
+// and writes it to w
+func (s *LogService) DownloadLogs(ctx context.Context, w io.Writer) (*Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/logs", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, w)
+}
+
+// TailLog writes the last lines of the Cloud Connector log to w. Cloud
+// Connector only offers the complete log archive for download, so the archive
+// is downloaded and the lines are taken from it.
+func (s *LogService) TailLog(ctx context.Context, lines int, w io.Writer) error {
+	logLines, err := s.readLogLines(ctx)
+	if err != nil {
+		return err
+	}
+	return writeLines(w, lastLines(logLines, lines))
+}
+
+// FollowLog works like TailLog and then keeps polling the log every interval,
+// writing lines added since the previous poll to w, until ctx is done. It
+// returns ctx.Err() once ctx is done. interval must be positive.
+func (s *LogService) FollowLog(ctx context.Context, lines int, w io.Writer, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid poll interval %v", interval)
+	}
+
+	logLines, err := s.readLogLines(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writeLines(w, lastLines(logLines, lines)); err != nil {
+		return err
+	}
+	seen := len(logLines)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		logLines, err := s.readLogLines(ctx)
+		if err != nil {
+			return err
+		}
+		if len(logLines) < seen {
+			// The log file was rotated; everything in it is new.
+			seen = 0
+		}
+		if err := writeLines(w, logLines[seen:]); err != nil {
+			return err
+		}
+		seen = len(logLines)
+	}
+}
+
+// readLogLines downloads the log archive and returns the lines of the main
+// log file.
+func (s *LogService) readLogLines(ctx context.Context) ([]string, error) {
+	var buf bytes.Buffer
+	if _, err := s.DownloadLogs(ctx, &buf); err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range archive.File {
+		if path.Base(f.Name) != traceLogFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(rc)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		return lines, scanner.Err()
+	}
+	return nil, errors.New("log archive does not contain " + traceLogFile)
+}
+
+func lastLines(lines []string, n int) []string {
+	if n >= 0 && len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
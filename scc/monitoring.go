@@ -0,0 +1,356 @@
+package scc
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type MonitoringService service
+
+// Connection is an open connection from a cloud application through a
+// subaccount tunnel to a backend system.
+type Connection struct {
+	RegionHost      string `json:"regionHost"`
+	Subaccount      string `json:"subaccount"`
+	LocationID      string `json:"locationID,omitempty"`
+	VirtualBackend  string `json:"virtualBackend"`
+	InternalBackend string `json:"internalBackend"`
+	Protocol        string `json:"protocol"`
+	Idle            int64  `json:"idle"`
+	Active          int64  `json:"active"`
+}
+
+// ConnectionFilter selects open connections. Zero-valued fields match any
+// connection.
+type ConnectionFilter struct {
+	RegionHost string // only connections of subaccounts in this region
+	Subaccount string // only connections of this subaccount
+	Protocol   string // only connections using this protocol
+	ActiveOnly bool   // only backends with at least one active connection
+}
+
+// Matches reports whether c is selected by f. A nil filter matches all
+// connections.
+func (f *ConnectionFilter) Matches(c *Connection) bool {
+	if f == nil {
+		return true
+	}
+	return (f.RegionHost == "" || f.RegionHost == c.RegionHost) &&
+		(f.Subaccount == "" || f.Subaccount == c.Subaccount) &&
+		(f.Protocol == "" || f.Protocol == c.Protocol) &&
+		(!f.ActiveOnly || c.Active > 0)
+}
+
+// GetOpenConnections gets the connections currently open to backend systems
+// that match filter, which may be nil. Cloud Connector does not support
+// filtering, so the filter is applied after the full list has been received.
+func (s *MonitoringService) GetOpenConnections(ctx context.Context, filter *ConnectionFilter) ([]*Connection, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/monitoring/connections/backends", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var connections []*Connection
+	resp, err := s.client.Do(ctx, req, &connections)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	matching := connections[:0]
+	for _, c := range connections {
+		if filter.Matches(c) {
+			matching = append(matching, c)
+		}
+	}
+	return matching, resp, nil
+}
+
+// StreamOpenConnections works like GetOpenConnections but decodes the response
+// one element at a time and calls fn for each connection, so memory use does
+// not grow with the number of open connections. Use ConnectionFilter.Matches
+// to select connections within fn. Streaming stops at the first
+// error returned by fn, which is then returned to the caller.
+func (s *MonitoringService) StreamOpenConnections(ctx context.Context, fn func(Connection) error) (*Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/monitoring/connections/backends", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.BareDo(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	return resp, streamArray(json.NewDecoder(resp.Body), func(dec *json.Decoder) error {
+		var c Connection
+		if err := dec.Decode(&c); err != nil {
+			return err
+		}
+		return fn(c)
+	})
+}
+
+// streamArray consumes a JSON array from dec, calling next once per element
+// with the decoder positioned at the start of that element.
+func streamArray(dec *json.Decoder, next func(*json.Decoder) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		if err := next(dec); err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// RecentRequest is one of the most recent requests processed through a
+// subaccount tunnel.
+type RecentRequest struct {
+	Timestamp    Timestamp     `json:"startTime"`
+	VirtualHost  string        `json:"virtualBackend"`
+	Path         string        `json:"resource"`
+	ResponseCode int           `json:"responseCode"`
+	Latency      time.Duration `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The connector
+// reports latencies in milliseconds.
+func (r *RecentRequest) UnmarshalJSON(data []byte) error {
+	type recentRequest RecentRequest
+	aux := struct {
+		*recentRequest
+		TotalTime int64 `json:"totalTime"`
+	}{recentRequest: (*recentRequest)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Latency = time.Duration(aux.TotalTime) * time.Millisecond
+	return nil
+}
+
+// GetRecentRequests gets the most recent requests processed through the tunnel
+// of a subaccount, newest first. If limit is positive, at most limit requests
+// are returned.
+func (s *MonitoringService) GetRecentRequests(ctx context.Context, regionHost, subaccount string, limit int) ([]*RecentRequest, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/monitoring/subaccounts/%v/%v/mostRecentRequests", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var requests []*RecentRequest
+	resp, err := s.client.Do(ctx, req, &requests)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if limit > 0 && len(requests) > limit {
+		requests = requests[:limit]
+	}
+	return requests, resp, nil
+}
+
+// ResetMonitoringStatistics resets the performance statistics collected by
+// Cloud Connector, e.g. to establish a fresh baseline before a load test
+func (s *MonitoringService) ResetMonitoringStatistics(ctx context.Context) (*Response, error) {
+	req, err := s.client.NewRequest("DELETE", "api/v1/monitoring/performance", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode != 204 {
+		return resp, errors.New("monitoring statistics reset failed with status code " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// TunnelLatency is the round-trip time of a subaccount tunnel to its region.
+type TunnelLatency struct {
+	Current time.Duration
+	Average time.Duration
+}
+
+// GetTunnelLatency gets the round-trip time of the tunnel of a subaccount. It
+// returns ErrNotSupported if the connector does not report tunnel latency.
+func (s *MonitoringService) GetTunnelLatency(ctx context.Context, regionHost, subaccount string) (*TunnelLatency, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/monitoring/subaccounts/%v/%v/tunnel/latency", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var latency struct {
+		CurrentInMillis int64 `json:"currentInMillis"`
+		AverageInMillis int64 `json:"averageInMillis"`
+	}
+	resp, err := s.client.Do(ctx, req, &latency)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &TunnelLatency{
+		Current: time.Duration(latency.CurrentInMillis) * time.Millisecond,
+		Average: time.Duration(latency.AverageInMillis) * time.Millisecond,
+	}, resp, nil
+}
+
+// BackendPerformance summarizes the request durations of a backend.
+type BackendPerformance struct {
+	RegionHost          string `json:"regionHost"`
+	Subaccount          string `json:"subaccount"`
+	VirtualBackend      string `json:"virtualBackend"`
+	Protocol            string `json:"protocol"`
+	Requests            int64  `json:"requests"`
+	MinimumTimeInMillis int64  `json:"minimumTimeInMillis"`
+	AverageTimeInMillis int64  `json:"averageTimeInMillis"`
+	MaximumTimeInMillis int64  `json:"maximumTimeInMillis"`
+}
+
+// GetPerformance gets performance statistics of the backends accessed through Cloud Connector
+func (s *MonitoringService) GetPerformance(ctx context.Context) ([]*BackendPerformance, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/monitoring/performance/backends", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var performance []*BackendPerformance
+	resp, err := s.client.Do(ctx, req, &performance)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return performance, resp, nil
+}
+
+// ExportConnectionsCSV writes the open connections to w as CSV with a header
+// row and the columns regionHost, subaccount, locationID, virtualBackend,
+// internalBackend, protocol, idle and active, in that order.
+func (s *MonitoringService) ExportConnectionsCSV(ctx context.Context, w io.Writer) (*Response, error) {
+	connections, resp, err := s.GetOpenConnections(ctx, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"regionHost", "subaccount", "locationID", "virtualBackend", "internalBackend", "protocol", "idle", "active"})
+	for _, c := range connections {
+		cw.Write([]string{c.RegionHost, c.Subaccount, c.LocationID, c.VirtualBackend, c.InternalBackend, c.Protocol, strconv.FormatInt(c.Idle, 10), strconv.FormatInt(c.Active, 10)})
+	}
+	cw.Flush()
+	return resp, cw.Error()
+}
+
+// ExportPerformanceCSV writes the backend performance statistics to w as CSV
+// with a header row and the columns regionHost, subaccount, virtualBackend,
+// protocol, requests, minimumTimeInMillis, averageTimeInMillis and
+// maximumTimeInMillis, in that order.
+func (s *MonitoringService) ExportPerformanceCSV(ctx context.Context, w io.Writer) (*Response, error) {
+	performance, resp, err := s.GetPerformance(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"regionHost", "subaccount", "virtualBackend", "protocol", "requests", "minimumTimeInMillis", "averageTimeInMillis", "maximumTimeInMillis"})
+	for _, p := range performance {
+		cw.Write([]string{p.RegionHost, p.Subaccount, p.VirtualBackend, p.Protocol, strconv.FormatInt(p.Requests, 10), strconv.FormatInt(p.MinimumTimeInMillis, 10), strconv.FormatInt(p.AverageTimeInMillis, 10), strconv.FormatInt(p.MaximumTimeInMillis, 10)})
+	}
+	cw.Flush()
+	return resp, cw.Error()
+}
+
+// ResourceStats is the access statistics of a resource of a system mapping.
+type ResourceStats struct {
+	ID         string     `json:"id"`
+	Hits       int64      `json:"hits"`
+	LastAccess *Timestamp `json:"lastAccess,omitempty"` // nil if never accessed
+}
+
+// GetResourceStats gets how often each resource of a system mapping has been
+// accessed. It returns ErrNotSupported if the connector does not collect
+// resource statistics.
+func (s *MonitoringService) GetResourceStats(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) ([]*ResourceStats, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/monitoring/subaccounts/%v/%v/systemMappings/%v:%v/resources", regionHost, subaccount, virtualHost, virtualPort)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stats []*ResourceStats
+	resp, err := s.client.Do(ctx, req, &stats)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if stats == nil {
+		stats = []*ResourceStats{}
+	}
+	return stats, resp, nil
+}
+
+// QueueMetrics describes the queue of requests waiting to be processed by
+// Cloud Connector.
+type QueueMetrics struct {
+	Depth    int64 `json:"depth"`    // requests currently queued
+	MaxDepth int64 `json:"maxDepth"` // highest depth observed
+	Rejected int64 `json:"rejected"` // requests rejected because the queue was full
+}
+
+// GetQueueMetrics gets the request queue metrics of Cloud Connector. It
+// returns ErrNotSupported if the connector does not report them.
+func (s *MonitoringService) GetQueueMetrics(ctx context.Context) (*QueueMetrics, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/monitoring/queue", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metrics := new(QueueMetrics)
+	resp, err := s.client.Do(ctx, req, metrics)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return metrics, resp, nil
+}
@@ -0,0 +1,70 @@
+package scc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Counters above 2^31 and 2^53+1, which a float64 cannot represent exactly.
+const (
+	above31 int64 = 1<<31 + 1
+	above53 int64 = 1<<53 + 1
+)
+
+func TestMonitoringService_largeCounters(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/api/v1/monitoring/connections/backends", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"regionHost":"cf.eu10.hana.ondemand.com","subaccount":"sa","virtualBackend":"vb:443","internalBackend":"ib:443","protocol":"HTTPS","idle":%d,"active":%d}]`, above31, above53)
+	})
+	mux.HandleFunc("/api/v1/monitoring/performance/backends", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"regionHost":"cf.eu10.hana.ondemand.com","subaccount":"sa","virtualBackend":"vb:443","protocol":"HTTPS","requests":%d,"minimumTimeInMillis":1,"averageTimeInMillis":%d,"maximumTimeInMillis":%d}]`, above53, above31, above53)
+	})
+	mux.HandleFunc("/api/v1/monitoring/queue", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"depth":%d,"maxDepth":%d,"rejected":%d}`, above31, above53, above53)
+	})
+	ctx := context.Background()
+
+	connections, _, err := client.Monitoring.GetOpenConnections(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetOpenConnections returned error: %v", err)
+	}
+	if c := connections[0]; c.Idle != above31 || c.Active != above53 {
+		t.Errorf("GetOpenConnections returned idle %d, active %d, want %d, %d", c.Idle, c.Active, above31, above53)
+	}
+
+	performance, _, err := client.Monitoring.GetPerformance(ctx)
+	if err != nil {
+		t.Fatalf("GetPerformance returned error: %v", err)
+	}
+	if p := performance[0]; p.Requests != above53 || p.AverageTimeInMillis != above31 || p.MaximumTimeInMillis != above53 {
+		t.Errorf("GetPerformance returned %+v", p)
+	}
+
+	metrics, _, err := client.Monitoring.GetQueueMetrics(ctx)
+	if err != nil {
+		t.Fatalf("GetQueueMetrics returned error: %v", err)
+	}
+	if want := (QueueMetrics{Depth: above31, MaxDepth: above53, Rejected: above53}); *metrics != want {
+		t.Errorf("GetQueueMetrics returned %+v, want %+v", *metrics, want)
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.Monitoring.ExportConnectionsCSV(ctx, &buf); err != nil {
+		t.Fatalf("ExportConnectionsCSV returned error: %v", err)
+	}
+	if want := fmt.Sprintf(",HTTPS,%d,%d\n", above31, above53); !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("ExportConnectionsCSV wrote %q, want suffix %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if _, err := client.Monitoring.ExportPerformanceCSV(ctx, &buf); err != nil {
+		t.Fatalf("ExportPerformanceCSV returned error: %v", err)
+	}
+	if want := fmt.Sprintf(",HTTPS,%d,1,%d,%d\n", above53, above31, above53); !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("ExportPerformanceCSV wrote %q, want suffix %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,79 @@
+package scc
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MultiError reports the failures of a bulk operation, keyed by the item that
+// failed, e.g. the string form of a SubaccountKey.
+type MultiError struct {
+	errs map[string]error
+}
+
+// Errors returns the failures keyed by item. The returned map is a copy.
+func (e *MultiError) Errors() map[string]error {
+	errs := make(map[string]error, len(e.errs))
+	for k, err := range e.errs {
+		errs[k] = err
+	}
+	return errs
+}
+
+func (e *MultiError) Error() string {
+	keys := make([]string, 0, len(e.errs))
+	for k := range e.errs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("failed for ")
+	b.WriteString(strconv.Itoa(len(keys)))
+	b.WriteString(" items")
+	for _, k := range keys {
+		b.WriteString("; ")
+		b.WriteString(k)
+		b.WriteString(": ")
+		b.WriteString(e.errs[k].Error())
+	}
+	return b.String()
+}
+
+// Is reports whether any of the failures matches target, so that errors.Is
+// can be used to test for e.g. ErrNotSupported.
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// subaccountErrors returns a *MultiError holding the non-nil errors of
+// results, or nil if there are none.
+func subaccountErrors(results map[SubaccountKey]error) error {
+	byName := make(map[string]error, len(results))
+	for k, err := range results {
+		byName[k.String()] = err
+	}
+	return itemErrors(byName)
+}
+
+// itemErrors returns a *MultiError holding the non-nil errors of results, or
+// nil if there are none.
+func itemErrors(results map[string]error) error {
+	errs := make(map[string]error)
+	for k, err := range results {
+		if err != nil {
+			errs[k] = err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &MultiError{errs: errs}
+}
@@ -0,0 +1,72 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+)
+
+// OutboundBinding selects the local address Cloud Connector uses for its
+// tunnel connections. Set either IP or Interface; both empty lets the
+// operating system choose.
+type OutboundBinding struct {
+	IP        string `json:"ip,omitempty"`        // e.g. "10.0.0.5"
+	Interface string `json:"interface,omitempty"` // e.g. "eth1"
+}
+
+// interfaceNamePattern matches network interface names such as eth0, ens192
+// or "Ethernet 2".
+var interfaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._:-]{0,63}$`)
+
+// GetOutboundBinding gets the local address used for tunnel connections. It
+// returns ErrNotSupported if the connector does not support binding.
+func (s *CommonService) GetOutboundBinding(ctx context.Context) (*OutboundBinding, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/tunnel/outboundBinding", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	binding := new(OutboundBinding)
+	resp, err := s.client.Do(ctx, req, binding)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return binding, resp, nil
+}
+
+// SetOutboundBinding sets the local address used for tunnel connections and
+// returns the effective binding. It returns ErrNotSupported if the connector
+// does not support binding.
+func (s *CommonService) SetOutboundBinding(ctx context.Context, binding *OutboundBinding) (*OutboundBinding, *Response, error) {
+	if binding.IP != "" && binding.Interface != "" {
+		return nil, nil, errors.New("outbound binding must not set both IP and interface")
+	}
+	if binding.IP != "" && net.ParseIP(binding.IP) == nil {
+		return nil, nil, fmt.Errorf("invalid IP address %q", binding.IP)
+	}
+	if binding.Interface != "" && !interfaceNamePattern.MatchString(binding.Interface) {
+		return nil, nil, fmt.Errorf("invalid interface name %q", binding.Interface)
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/tunnel/outboundBinding", binding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(OutboundBinding)
+	resp, err := s.client.Do(ctx, req, updated)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
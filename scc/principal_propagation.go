@@ -0,0 +1,272 @@
+package scc
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+type PrincipalPropagationService service
+
+// TrustedCA is a CA certificate trusted for principal propagation.
+type TrustedCA struct {
+	Subject     string `json:"subjectDN"`
+	Issuer      string `json:"issuerDN,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// ListSubaccountTrustedCAs lists the CAs trusted for principal propagation of a subaccount
+func (s *PrincipalPropagationService) ListSubaccountTrustedCAs(ctx context.Context, regionHost, subaccount string) ([]*TrustedCA, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/principalPropagation/trustedCAs", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cas []*TrustedCA
+	resp, err := s.client.Do(ctx, req, &cas)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cas, resp, nil
+}
+
+// AddSubaccountTrustedCA adds a PEM encoded CA certificate to the CAs trusted
+// for principal propagation of a subaccount
+func (s *PrincipalPropagationService) AddSubaccountTrustedCA(ctx context.Context, regionHost, subaccount string, certPEM []byte) (*TrustedCA, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/principalPropagation/trustedCAs", regionHost, subaccount)
+	req, err := s.client.NewRequest("POST", u, struct {
+		Certificate string `json:"certificate"`
+	}{Certificate: string(certPEM)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ca := new(TrustedCA)
+	resp, err := s.client.Do(ctx, req, ca)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ca, resp, nil
+}
+
+// TrustedCAResult is the outcome of adding one certificate of a bundle.
+type TrustedCAResult struct {
+	PEM []byte     // the certificate as found in the bundle
+	CA  *TrustedCA // the added CA, nil if Err is set
+	Err error
+}
+
+// AddSubaccountTrustedCABundle adds every certificate of a PEM encoded bundle
+// to the CAs trusted for principal propagation of a subaccount, like
+// AddTrustedCABundle does for the connector.
+func (s *PrincipalPropagationService) AddSubaccountTrustedCABundle(ctx context.Context, regionHost, subaccount string, bundle []byte) ([]*TrustedCAResult, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	return addTrustedCABundle(bundle, func(certPEM []byte) (*TrustedCA, error) {
+		ca, _, err := s.AddSubaccountTrustedCA(ctx, regionHost, subaccount, certPEM)
+		return ca, err
+	})
+}
+
+// ListTrustedCAs lists the CAs trusted for principal propagation by the
+// connector, i.e. for all subaccounts. It returns ErrNotSupported if the
+// connector only manages trusted CAs per subaccount.
+func (s *PrincipalPropagationService) ListTrustedCAs(ctx context.Context) ([]*TrustedCA, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/principalPropagation/trustedCAs", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cas []*TrustedCA
+	resp, err := s.client.Do(ctx, req, &cas)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if cas == nil {
+		cas = []*TrustedCA{}
+	}
+	return cas, resp, nil
+}
+
+// AddTrustedCA adds a PEM encoded CA certificate to the CAs trusted for
+// principal propagation by the connector. It returns ErrNotSupported if the
+// connector only manages trusted CAs per subaccount.
+func (s *PrincipalPropagationService) AddTrustedCA(ctx context.Context, certPEM []byte) (*TrustedCA, *Response, error) {
+	req, err := s.client.NewRequest("POST", "api/v1/configuration/connector/principalPropagation/trustedCAs", struct {
+		Certificate string `json:"certificate"`
+	}{Certificate: string(certPEM)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ca := new(TrustedCA)
+	resp, err := s.client.Do(ctx, req, ca)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ca, resp, nil
+}
+
+// AddTrustedCABundle adds every certificate of a PEM encoded bundle to the CAs
+// trusted for principal propagation by the connector. Certificates are added
+// one by one in bundle order; a failure is recorded in that certificate's
+// result and does not stop the remaining ones. Blocks that do not parse as
+// certificates are reported without being sent. An error is only returned if
+// the bundle contains no PEM blocks at all.
+func (s *PrincipalPropagationService) AddTrustedCABundle(ctx context.Context, pemBundle []byte) ([]*TrustedCAResult, error) {
+	return addTrustedCABundle(pemBundle, func(certPEM []byte) (*TrustedCA, error) {
+		ca, _, err := s.AddTrustedCA(ctx, certPEM)
+		return ca, err
+	})
+}
+
+// addTrustedCABundle splits bundle into certificates and calls add for each
+// one that parses.
+func addTrustedCABundle(bundle []byte, add func(certPEM []byte) (*TrustedCA, error)) ([]*TrustedCAResult, error) {
+	var results []*TrustedCAResult
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+
+		result := &TrustedCAResult{PEM: pem.EncodeToMemory(block)}
+		results = append(results, result)
+		if block.Type != "CERTIFICATE" {
+			result.Err = fmt.Errorf("unexpected PEM block type %q", block.Type)
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			result.Err = err
+			continue
+		}
+
+		result.CA, result.Err = add(result.PEM)
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return results, nil
+}
+
+// DeleteSubaccountTrustedCA removes the CA with the given fingerprint from the
+// CAs trusted for principal propagation of a subaccount
+func (s *PrincipalPropagationService) DeleteSubaccountTrustedCA(ctx context.Context, regionHost, subaccount, fingerprint string) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/principalPropagation/trustedCAs/%v", regionHost, subaccount, fingerprint)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// PrincipalPropagationSession is a user identity currently being forwarded to
+// a backend system.
+type PrincipalPropagationSession struct {
+	User       string    `json:"user"`
+	RegionHost string    `json:"regionHost"`
+	Subaccount string    `json:"subaccount"`
+	Backend    string    `json:"virtualBackend"`
+	StartTime  Timestamp `json:"startTime"`
+}
+
+// ListPrincipalPropagationSessions lists the active principal propagation
+// sessions. It returns ErrNotSupported if the connector does not report them.
+func (s *PrincipalPropagationService) ListPrincipalPropagationSessions(ctx context.Context) ([]*PrincipalPropagationSession, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/monitoring/principalPropagation/sessions", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []*PrincipalPropagationSession
+	resp, err := s.client.Do(ctx, req, &sessions)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if sessions == nil {
+		sessions = []*PrincipalPropagationSession{}
+	}
+	return sessions, resp, nil
+}
+
+// ErrPrincipalPropagationFailed is returned by TestPrincipalPropagation when
+// the connector could not issue a certificate for the sample user, typically
+// because of an incomplete trust chain.
+var ErrPrincipalPropagationFailed = errors.New("principal propagation test failed")
+
+// PrincipalPropagationTestResult is the outcome of TestPrincipalPropagation.
+type PrincipalPropagationTestResult struct {
+	Success bool   `json:"success"`
+	Subject string `json:"subject,omitempty"` // subject of the generated short-lived certificate
+	Reason  string `json:"reason,omitempty"`  // why the test failed
+}
+
+// TestPrincipalPropagation lets the connector issue a short-lived certificate
+// for sampleUser, checking the principal propagation setup of a subaccount end
+// to end. If the connector reports a failure, the result is returned together
+// with an error wrapping ErrPrincipalPropagationFailed that carries the reason.
+// It returns ErrNotSupported if the connector has no test endpoint.
+func (s *PrincipalPropagationService) TestPrincipalPropagation(ctx context.Context, regionHost, subaccount, sampleUser string) (*PrincipalPropagationTestResult, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+	if sampleUser == "" {
+		return nil, nil, errors.New("sample user must not be empty")
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/principalPropagation/test", regionHost, subaccount)
+	req, err := s.client.NewRequest("POST", u, struct {
+		User string `json:"user"`
+	}{User: sampleUser})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(PrincipalPropagationTestResult)
+	resp, err := s.client.Do(ctx, req, result)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if !result.Success {
+		return result, resp, fmt.Errorf("%w: %v", ErrPrincipalPropagationFailed, result.Reason)
+	}
+	return result, resp, nil
+}
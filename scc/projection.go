@@ -0,0 +1,43 @@
+package scc
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Cloud Connector does not support server-side field selection: none of its
+// endpoints accept a fields or projection query parameter, so list methods
+// always return complete objects. Project can be used to reduce decoded
+// results to the fields a caller actually needs, e.g. before caching them or
+// handing them to a monitoring backend.
+
+// Project reduces v to the given JSON fields. v may be a struct, a pointer to
+// a struct or a slice of either; the result holds one map per element, keyed
+// by JSON field name. Fields that v does not have are omitted.
+func Project(v interface{}, fields ...string) ([]map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		v = []interface{}{v}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all []map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]interface{}, 0, len(all))
+	for _, m := range all {
+		p := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if val, ok := m[f]; ok {
+				p[f] = val
+			}
+		}
+		projected = append(projected, p)
+	}
+	return projected, nil
+}
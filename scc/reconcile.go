@@ -0,0 +1,410 @@
+package scc
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// ReconcileOptions configures Reconcile.
+type ReconcileOptions struct {
+	// DryRun computes the changes without applying any of them.
+	DryRun bool
+
+	// Prune deletes subaccounts, system mappings, resources and service
+	// channels that are not part of the desired configuration. Without it,
+	// Reconcile only creates and updates.
+	Prune bool
+
+	// Concurrency bounds the number of subaccounts reconciled in parallel.
+	// Defaults to 8.
+	Concurrency int
+}
+
+// Actions of a ConfigChange.
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Kinds of configuration items changed by Reconcile.
+const (
+	ChangeKindDescription           = "description"
+	ChangeKindMasterConfiguration   = "masterConfiguration"
+	ChangeKindSubaccount            = "subaccount"
+	ChangeKindAuditLevel            = "auditLevel"
+	ChangeKindSystemMapping         = "systemMapping"
+	ChangeKindSystemMappingResource = "systemMappingResource"
+	ChangeKindServiceChannel        = "serviceChannel"
+)
+
+// ConfigChange is a change made, or in a dry run planned, by Reconcile.
+type ConfigChange struct {
+	Action     string
+	Kind       string
+	Subaccount SubaccountKey // zero for connector-wide changes
+	Item       string        // e.g. "host:port" of a system mapping
+	Err        error         // why applying the change failed
+}
+
+// ReconcileReport lists the changes of a Reconcile call in the order they
+// were applied per subaccount.
+type ReconcileReport struct {
+	Changes []ConfigChange
+}
+
+// Reconcile changes the configuration of the connector to match desired,
+// applying only the differences to the current configuration read with
+// ExportConfig. Within a subaccount, changes are ordered so that the
+// subaccount exists before its system mappings and a mapping before its
+// resources; deletions run in reverse order. Subaccounts are reconciled
+// concurrently and independently: the first failing change stops the
+// reconciliation of its subaccount only, and the failures are returned as a
+// *MultiError keyed by subaccount together with the report. opts may be nil.
+//
+// Like ImportConfig, Reconcile never changes the high availability role and
+// creating a subaccount requires its CloudUser and CloudPassword.
+func (c *Client) Reconcile(ctx context.Context, desired *ConnectorConfig, opts *ReconcileOptions) (*ReconcileReport, error) {
+	if opts == nil {
+		opts = &ReconcileOptions{}
+	}
+	if err := validateSubaccountConfigs(desired.Subaccounts); err != nil {
+		return nil, err
+	}
+
+	// Desired subaccounts may rely on the default region host, so their keys
+	// are resolved before they are matched with the existing ones.
+	want := make(map[SubaccountKey]*SubaccountConfig, len(desired.Subaccounts))
+	keys := make([]SubaccountKey, 0, len(desired.Subaccounts))
+	for _, sa := range desired.Subaccounts {
+		k := SubaccountKey{RegionHost: sa.RegionHost, Subaccount: sa.Subaccount.Subaccount}
+		if err := c.resolveSubaccountKey(&k.RegionHost, k.Subaccount); err != nil {
+			return nil, err
+		}
+		want[k] = sa
+		keys = append(keys, k)
+	}
+
+	current, err := c.ExportConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reconciler{client: c, opts: opts}
+	if desired.Common != nil && desired.Common.Description != current.Common.Description {
+		err := r.apply(ConfigChange{Action: ChangeUpdate, Kind: ChangeKindDescription}, func() error {
+			_, _, err := c.Common.SetDescription(ctx, desired.Common.Description)
+			return err
+		})
+		if err != nil {
+			return r.report(), err
+		}
+	}
+	if desired.HA != nil && desired.HA.Master != nil && (current.HA.Master == nil || !reflect.DeepEqual(desired.HA.Master, current.HA.Master)) {
+		err := r.apply(ConfigChange{Action: ChangeUpdate, Kind: ChangeKindMasterConfiguration}, func() error {
+			_, _, err := c.HA.SetMasterConfiguration(ctx, desired.HA.Master)
+			return err
+		})
+		if err != nil {
+			return r.report(), err
+		}
+	}
+
+	have := make(map[SubaccountKey]*SubaccountConfig, len(current.Subaccounts))
+	for _, sa := range current.Subaccounts {
+		have[SubaccountKey{RegionHost: sa.RegionHost, Subaccount: sa.Subaccount.Subaccount}] = sa
+	}
+	results := make(map[SubaccountKey]error, len(keys))
+	var mu sync.Mutex
+	forEachSubaccount(keys, opts.Concurrency, func(k SubaccountKey) {
+		err := r.subaccount(ctx, k, want[k], have[k])
+
+		mu.Lock()
+		results[k] = err
+		mu.Unlock()
+	})
+
+	if opts.Prune {
+		for k := range have {
+			if want[k] != nil {
+				continue
+			}
+			k := k
+			results[k] = r.apply(ConfigChange{Action: ChangeDelete, Kind: ChangeKindSubaccount, Subaccount: k}, func() error {
+				_, err := c.Subaccount.DeleteSubaccount(ctx, k.RegionHost, k.Subaccount)
+				return err
+			})
+		}
+	}
+
+	return r.report(), subaccountErrors(results)
+}
+
+// reconciler records the changes of a Reconcile call. It is safe for
+// concurrent use.
+type reconciler struct {
+	client *Client
+	opts   *ReconcileOptions
+
+	mu      sync.Mutex
+	changes []ConfigChange
+}
+
+// apply records change and, unless in a dry run, makes it by calling fn.
+func (r *reconciler) apply(change ConfigChange, fn func() error) error {
+	if !r.opts.DryRun {
+		change.Err = fn()
+	}
+
+	r.mu.Lock()
+	r.changes = append(r.changes, change)
+	r.mu.Unlock()
+	return change.Err
+}
+
+func (r *reconciler) report() *ReconcileReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &ReconcileReport{Changes: append([]ConfigChange(nil), r.changes...)}
+}
+
+// subaccount reconciles a single subaccount, have being nil if it does not
+// exist yet.
+func (r *reconciler) subaccount(ctx context.Context, k SubaccountKey, want, have *SubaccountConfig) error {
+	c := r.client
+	rh, sa := k.RegionHost, k.Subaccount
+	change := ConfigChange{Kind: ChangeKindSubaccount, Subaccount: k}
+
+	if have == nil {
+		change.Action = ChangeCreate
+		err := r.apply(change, func() error {
+			_, _, err := c.Subaccount.AddSubaccount(ctx, want.Subaccount)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		have = &SubaccountConfig{Subaccount: &Subaccount{}}
+	} else if !equalSubaccount(want.Subaccount, have.Subaccount) {
+		change.Action = ChangeUpdate
+		err := r.apply(change, func() error {
+			_, _, err := c.Subaccount.UpdateSubaccount(ctx, rh, sa, want.Subaccount)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if want.AuditLevel != "" && want.AuditLevel != have.AuditLevel {
+		err := r.apply(ConfigChange{Action: ChangeUpdate, Kind: ChangeKindAuditLevel, Subaccount: k}, func() error {
+			_, err := c.Subaccount.SetAuditLevel(ctx, rh, sa, want.AuditLevel)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.systemMappings(ctx, k, want.SystemMappings, have.SystemMappings); err != nil {
+		return err
+	}
+
+	for _, channelType := range channelTypes {
+		if err := r.serviceChannels(ctx, k, channelType, want.ServiceChannels[channelType], have.ServiceChannels[channelType]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) systemMappings(ctx context.Context, k SubaccountKey, want, have []*SystemMappingConfig) error {
+	c := r.client
+	rh, sa := k.RegionHost, k.Subaccount
+
+	existing := make(map[string]*SystemMappingConfig, len(have))
+	for _, m := range have {
+		existing[m.VirtualHost+":"+m.VirtualPort] = m
+	}
+	wanted := make(map[string]bool, len(want))
+
+	for _, m := range want {
+		m := m
+		id := m.VirtualHost + ":" + m.VirtualPort
+		wanted[id] = true
+		change := ConfigChange{Kind: ChangeKindSystemMapping, Subaccount: k, Item: id}
+
+		current := existing[id]
+		if current == nil {
+			change.Action = ChangeCreate
+			err := r.apply(change, func() error {
+				_, err := c.SystemMapping.CreateSystemMapping(ctx, rh, sa, m.SystemMapping)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			current = &SystemMappingConfig{}
+		} else if !reflect.DeepEqual(m.SystemMapping, current.SystemMapping) {
+			change.Action = ChangeUpdate
+			err := r.apply(change, func() error {
+				_, err := c.SystemMapping.UpdateSystemMapping(ctx, rh, sa, m.SystemMapping)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := r.systemMappingResources(ctx, k, m, current.Resources); err != nil {
+			return err
+		}
+	}
+
+	if !r.opts.Prune {
+		return nil
+	}
+	for _, m := range have {
+		m := m
+		id := m.VirtualHost + ":" + m.VirtualPort
+		if wanted[id] {
+			continue
+		}
+		err := r.apply(ConfigChange{Action: ChangeDelete, Kind: ChangeKindSystemMapping, Subaccount: k, Item: id}, func() error {
+			_, err := c.SystemMapping.DeleteSystemMapping(ctx, rh, sa, m.VirtualHost, m.VirtualPort)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) systemMappingResources(ctx context.Context, k SubaccountKey, mapping *SystemMappingConfig, have []*SystemMappingResource) error {
+	c := r.client
+	rh, sa, vh, vp := k.RegionHost, k.Subaccount, mapping.VirtualHost, mapping.VirtualPort
+
+	existing := make(map[string]*SystemMappingResource, len(have))
+	for _, res := range have {
+		existing[res.ID] = res
+	}
+	wanted := make(map[string]bool, len(mapping.Resources))
+
+	for _, res := range mapping.Resources {
+		res := res
+		wanted[res.ID] = true
+		change := ConfigChange{Kind: ChangeKindSystemMappingResource, Subaccount: k, Item: vh + ":" + vp + "/" + res.ID}
+
+		var err error
+		switch current := existing[res.ID]; {
+		case current == nil:
+			change.Action = ChangeCreate
+			err = r.apply(change, func() error {
+				_, err := c.SystemMapping.CreateSystemMappingResource(ctx, rh, sa, vh, vp, res)
+				return err
+			})
+		case *current != *res:
+			change.Action = ChangeUpdate
+			err = r.apply(change, func() error {
+				_, err := c.SystemMapping.UpdateSystemMappingResource(ctx, rh, sa, vh, vp, res)
+				return err
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !r.opts.Prune {
+		return nil
+	}
+	for _, res := range have {
+		res := res
+		if wanted[res.ID] {
+			continue
+		}
+		err := r.apply(ConfigChange{Action: ChangeDelete, Kind: ChangeKindSystemMappingResource, Subaccount: k, Item: vh + ":" + vp + "/" + res.ID}, func() error {
+			_, err := c.SystemMapping.DeleteSystemMappingResource(ctx, rh, sa, vh, vp, res.ID)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serviceChannels reconciles the channels of one type. As channel IDs are
+// assigned by the connector, channels are matched by their details.
+func (r *reconciler) serviceChannels(ctx context.Context, k SubaccountKey, channelType ChannelType, want, have []*ServiceChannel) error {
+	c := r.client
+	rh, sa := k.RegionHost, k.Subaccount
+
+	existing := make(map[string]*ServiceChannel, len(have))
+	for _, ch := range have {
+		existing[ch.Details] = ch
+	}
+	wanted := make(map[string]bool, len(want))
+
+	for _, ch := range want {
+		wanted[ch.Details] = true
+		chCopy := *ch
+		chCopy.State = nil
+		change := ConfigChange{Kind: ChangeKindServiceChannel, Subaccount: k, Item: string(channelType) + "/" + ch.Details}
+
+		var err error
+		switch current := existing[ch.Details]; {
+		case current == nil:
+			change.Action = ChangeCreate
+			err = r.apply(change, func() error {
+				_, err := c.ServiceChannel.CreateServiceChannel(ctx, rh, sa, channelType, &chCopy)
+				return err
+			})
+		case !equalServiceChannel(&chCopy, current):
+			chCopy.ID = current.ID
+			change.Action = ChangeUpdate
+			err = r.apply(change, func() error {
+				_, err := c.ServiceChannel.UpdateServiceChannel(ctx, rh, sa, channelType, &chCopy)
+				return err
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !r.opts.Prune {
+		return nil
+	}
+	for _, ch := range have {
+		ch := ch
+		if wanted[ch.Details] {
+			continue
+		}
+		err := r.apply(ConfigChange{Action: ChangeDelete, Kind: ChangeKindServiceChannel, Subaccount: k, Item: string(channelType) + "/" + ch.Details}, func() error {
+			_, err := c.ServiceChannel.DeleteServiceChannel(ctx, rh, sa, channelType, ch.ID)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// equalSubaccount compares the configuration of two subaccounts, ignoring
+// credentials and tunnel state.
+func equalSubaccount(a, b *Subaccount) bool {
+	return a.LocationID == b.LocationID && a.DisplayName == b.DisplayName && a.Description == b.Description
+}
+
+// equalServiceChannel compares the configuration of two service channels,
+// ignoring their IDs and state.
+func equalServiceChannel(a, b *ServiceChannel) bool {
+	x, y := *a, *b
+	x.ID, y.ID = 0, 0
+	x.State, y.State = nil, nil
+	return x == y
+}
@@ -0,0 +1,52 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_Reconcile_defaultRegionHostPrune(t *testing.T) {
+	const other = "1d3d2c5b-8f1a-4b6f-8d4e-3c2b1a0f9e8d"
+	client, mux := setup(t, WithDefaultRegionHost(testRegionHost))
+
+	var mu sync.Mutex
+	var changes []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/configuration/")
+		if r.Method != "GET" {
+			mu.Lock()
+			changes = append(changes, r.Method+" "+path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		switch {
+		case path == "connector":
+			fmt.Fprint(w, `{"description":""}`)
+		case path == "connector/haRole":
+			fmt.Fprint(w, "shadow")
+		case path == "subaccounts":
+			fmt.Fprintf(w, `[{"regionHost":%q,"subaccount":%q,"displayName":"kept"},{"regionHost":%q,"subaccount":%q,"displayName":"pruned"}]`, testRegionHost, testSubaccount, testRegionHost, other)
+		case strings.HasSuffix(path, "/auditLogs"):
+			fmt.Fprint(w, `{"auditLevel":"SECURITY"}`)
+		default:
+			fmt.Fprint(w, "[]")
+		}
+	})
+
+	desired := &ConnectorConfig{Subaccounts: []*SubaccountConfig{{
+		Subaccount: &Subaccount{Subaccount: testSubaccount, DisplayName: "kept"},
+	}}}
+	if _, err := client.Reconcile(context.Background(), desired, &ReconcileOptions{Prune: true}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	want := []string{"DELETE subaccounts/" + testRegionHost + "/" + other}
+	if len(changes) != len(want) || changes[0] != want[0] {
+		t.Errorf("Reconcile made changes %q, want %q", changes, want)
+	}
+}
@@ -0,0 +1,222 @@
+package scc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// redactedHeaders are never written to a replay file.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-CSRF-Token"}
+
+// WithReplayFile makes the client record its HTTP interactions to path, or
+// replay them from path if the file already exists. This allows tests to run
+// deterministically against responses captured from a real Cloud Connector.
+//
+// Credentials are redacted before an interaction is recorded: sensitive
+// headers are replaced and any JSON body field whose name contains "password"
+// or "secret" is masked.
+func WithReplayFile(path string) ClientOption {
+	return func(c *Client) error {
+		t := &ReplayTransport{Path: path}
+		data, err := ioutil.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &t.interactions); err != nil {
+				return fmt.Errorf("reading replay file %v: %v", path, err)
+			}
+			t.replay = true
+		case !os.IsNotExist(err):
+			return err
+		}
+
+		c.withTransport(func(rt http.RoundTripper) http.RoundTripper {
+			t.Transport = rt
+			return t
+		})
+		return nil
+	}
+}
+
+// ReplayTransport is an http.RoundTripper that either records the
+// interactions performed through Transport to Path, or replays previously
+// recorded interactions without touching the network. Use WithReplayFile to
+// install it on a Client.
+type ReplayTransport struct {
+	Path string // file holding the recorded interactions
+
+	// Transport is the underlying HTTP transport used while recording.
+	Transport http.RoundTripper
+
+	mu           sync.Mutex
+	replay       bool
+	interactions []*interaction
+	used         []bool
+}
+
+type interaction struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+		Body   []byte      `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       []byte      `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.replay {
+		return t.replayRoundTrip(req)
+	}
+	return t.recordRoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the underlying
+// transport.
+func (t *ReplayTransport) CloseIdleConnections() {
+	if t.Transport != nil {
+		closeIdleConnections(t.Transport)
+	}
+}
+
+func (t *ReplayTransport) unwrap() http.RoundTripper {
+	return t.Transport
+}
+
+func (t *ReplayTransport) rewrap(inner http.RoundTripper) http.RoundTripper {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &ReplayTransport{
+		Path:         t.Path,
+		Transport:    inner,
+		replay:       t.replay,
+		interactions: append([]*interaction(nil), t.interactions...),
+		used:         append([]bool(nil), t.used...),
+	}
+}
+
+// replayRoundTrip answers req with the first unused recorded interaction
+// that has the same method and URL.
+func (t *ReplayTransport) replayRoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.used == nil {
+		t.used = make([]bool, len(t.interactions))
+	}
+
+	for i, in := range t.interactions {
+		if t.used[i] || in.Request.Method != req.Method || in.Request.URL != req.URL.String() {
+			continue
+		}
+		t.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header,
+			Body:          ioutil.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded response for %v %v", req.Method, req.URL)
+}
+
+// recordRoundTrip performs req and appends the redacted interaction to the
+// replay file.
+func (t *ReplayTransport) recordRoundTrip(req *http.Request) (*http.Response, error) {
+	in := new(interaction)
+	in.Request.Method = req.Method
+	in.Request.URL = req.URL.String()
+	in.Request.Header = redactHeader(req.Header)
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		in.Request.Body = redactBody(body)
+	}
+
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	in.Response.StatusCode = resp.StatusCode
+	in.Response.Header = redactHeader(resp.Header)
+	in.Response.Body = redactBody(body)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interactions = append(t.interactions, in)
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(t.Path, data, 0600); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, k := range redactedHeaders {
+		if redacted.Get(k) != "" {
+			redacted.Set(k, "REDACTED")
+		}
+	}
+	return redacted
+}
+
+// redactBody masks sensitive fields of a JSON body. Bodies that are not JSON,
+// such as backup archives, are returned unchanged.
+func redactBody(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			name := strings.ToLower(k)
+			if strings.Contains(name, "password") || strings.Contains(name, "secret") {
+				v[k] = "REDACTED"
+				continue
+			}
+			v[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val)
+		}
+	}
+	return v
+}
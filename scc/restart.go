@@ -0,0 +1,35 @@
+package scc
+
+import (
+	"context"
+)
+
+// RestartInfo describes the last restart of Cloud Connector.
+type RestartInfo struct {
+	Time   Timestamp `json:"time"`
+	Reason string    `json:"reason,omitempty"` // empty if the connector does not know
+}
+
+// GetLastRestart gets when and why Cloud Connector was last restarted. It
+// returns ErrNotSupported if the connector does not report restarts, as is
+// the case for older versions.
+func (s *CommonService) GetLastRestart(ctx context.Context) (*RestartInfo, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/connector/lastRestart", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := new(RestartInfo)
+	resp, err := s.client.Do(ctx, req, info)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+	if info.Time.IsZero() {
+		return nil, resp, ErrNotSupported
+	}
+
+	return info, resp, nil
+}
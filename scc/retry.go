@@ -0,0 +1,149 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// defaultRetryableStatusCodes are the status codes that trigger a retry
+// unless configured otherwise with WithRetryableStatusCodes.
+var defaultRetryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type retryConfig struct {
+	maxRetries  int
+	backoff     time.Duration
+	statusCodes map[int]bool
+	hook        func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	Method     string
+	Path       string
+	Attempt    int           // number of the failed attempt, starting at 1
+	StatusCode int           // status code of the failed attempt, 0 if no response was received
+	Err        error         // error of the failed attempt
+	Delay      time.Duration // wait before the next attempt
+}
+
+// WithRetryHook registers fn to be called before every retry, e.g. to log or
+// count retries and tune the configuration of WithRetry. fn is called
+// synchronously and may be called concurrently for concurrent requests.
+func WithRetryHook(fn func(RetryEvent)) ClientOption {
+	return func(c *Client) error {
+		c.retry.hook = fn
+		return nil
+	}
+}
+
+// WithRetry makes the client retry requests that fail with a network error or
+// a retryable status code up to maxRetries times. The delay before the first
+// retry is backoff and doubles with every further retry. Requests whose body
+// cannot be replayed, such as file uploads, are never retried.
+func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) error {
+		if maxRetries < 0 {
+			return fmt.Errorf("invalid number of retries %d", maxRetries)
+		}
+		c.retry.maxRetries = maxRetries
+		c.retry.backoff = backoff
+		return nil
+	}
+}
+
+// WithRetryableStatusCodes adds codes to the status codes that trigger a
+// retry, which by default are 502, 503 and 504. Only client and server error
+// codes (4xx and 5xx) are accepted. It has no effect unless retries are
+// enabled with WithRetry.
+func WithRetryableStatusCodes(codes ...int) ClientOption {
+	return func(c *Client) error {
+		for _, code := range codes {
+			if code < 400 || code > 599 {
+				return fmt.Errorf("status code %d is not a client or server error", code)
+			}
+		}
+		for _, code := range codes {
+			c.retry.statusCodes[code] = true
+		}
+		return nil
+	}
+}
+
+// RetryableStatusCodes returns the status codes that trigger a retry, in
+// ascending order.
+func (c *Client) RetryableStatusCodes() []int {
+	codes := make([]int, 0, len(c.retry.statusCodes))
+	for code := range c.retry.statusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+type withoutRetryContextKey struct{}
+
+// WithoutRetry returns a copy of ctx in which requests are sent only once,
+// e.g. for a liveness probe that should fail fast. It takes precedence over
+// the retries configured with WithRetry for all requests made with the
+// returned context. The state change retries configured with WithHARetry are
+// not affected.
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutRetryContextKey{}, true)
+}
+
+func newRetryConfig() retryConfig {
+	r := retryConfig{}
+	r.statusCodes = make(map[int]bool, len(defaultRetryableStatusCodes))
+	for _, code := range defaultRetryableStatusCodes {
+		r.statusCodes[code] = true
+	}
+	return r
+}
+
+// shouldRetry reports whether req, having failed attempt with resp and err,
+// should be sent again.
+func (c *Client) shouldRetry(ctx context.Context, req *http.Request, attempt int, resp *Response, err error) bool {
+	if err == nil || attempt >= c.retry.maxRetries || ctx.Err() != nil {
+		return false
+	}
+	if disabled, _ := ctx.Value(withoutRetryContextKey{}).(bool); disabled {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if resp == nil {
+		return true
+	}
+	return c.retry.statusCodes[resp.StatusCode]
+}
+
+// waitRetry sleeps before the retry following attempt, returning early with
+// the context's error if ctx is done. The retry is reported to the hook
+// configured with WithRetryHook, if any.
+func (c *Client) waitRetry(ctx context.Context, req *http.Request, attempt int, resp *Response, err error) error {
+	delay := c.retry.backoff << uint(attempt)
+	if c.retry.hook != nil {
+		event := RetryEvent{Method: req.Method, Path: req.URL.Path, Attempt: attempt + 1, Err: err, Delay: delay}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+		}
+		c.retry.hook(event)
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -3,6 +3,8 @@ package scc
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +14,8 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -19,6 +23,9 @@ const (
 
 	defaultMediaType = "application/octet-stream"
 
+	// defaultMaxResponseBytes limits the size of response bodies decoded by Do.
+	defaultMaxResponseBytes = 32 << 20
+
 	// headerSessionID  = "Set-cookie"
 	// headerCookie     = "Cookie"
 	// headerCSRFToken  = "X-CSRF-Token"
@@ -27,6 +34,22 @@ const (
 
 var errNonNilContext = errors.New("context must be non-nil")
 
+// ErrNoShadowURL is returned when a request to the shadow instance is made
+// without a shadow URL configured with WithShadowURL.
+var ErrNoShadowURL = errors.New("no shadow URL configured")
+
+// ErrResponseTooLarge is returned by Do when a response body exceeds the limit
+// set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrNotSupported is returned when the connector does not provide the
+// requested information, typically because its version predates it.
+var ErrNotSupported = errors.New("not supported by this Cloud Connector version")
+
+// ErrClientClosed is returned for requests made through a Client after Close
+// has been called.
+var ErrClientClosed = errors.New("client closed")
+
 // A Client manages communication with the SCC API.
 type Client struct {
 	clientMu sync.Mutex   // clientMu protects the client during calls that modify the CheckRedirect func.
@@ -36,14 +59,49 @@ type Client struct {
 	// always be specified with a trailing slash.
 	BaseURL *url.URL
 
+	// Base URL of the shadow instance of a high availability setup, used
+	// for requests built with NewShadowRequest. Like BaseURL it should have
+	// a trailing slash. It is nil unless configured with WithShadowURL.
+	ShadowURL *url.URL
+
 	// User agent used when communicating with the GitHub API.
 	UserAgent string
 
+	retry retryConfig // retry behavior, configured with WithRetry.
+	dial  dialConfig  // connection settings, configured with WithKeepAlive and friends.
+
+	haRetry            haRetryConfig // HA state change retries, configured with WithHARetry.
+	verifyShadowConfig bool          // re-read the shadow configuration after setting it, configured with WithShadowConfigVerification.
+
+	maxResponseBytes int64 // limit for decoded response bodies, configured with WithMaxResponseBytes.
+	strictJSON       bool  // reject unknown fields when decoding, configured with WithStrictJSON.
+
+	zipMediaTypeFallback bool // retry backup uploads as application/zip, configured with WithZipMediaTypeFallback.
+
+	defaultRegionHost string // region host used when a call passes none, configured with WithDefaultRegionHost.
+
+	closed int32 // set to 1 by Close, accessed atomically.
+
+	defaultsMu      sync.Mutex       // defaultsMu protects backendDefaults.
+	backendDefaults *BackendTimeouts // connector-wide backend timeouts, cached by backendTimeouts.
+
+	haPauseMu sync.Mutex           // haPauseMu serializes PauseHAChecks and ResumeHAChecks and protects haPaused.
+	haPaused  *ShadowConfiguration // shadow configuration saved by PauseHAChecks, nil while the checks are not paused.
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for talking to different parts of the SCC API.
-	Common *CommonService
-	Backup *BackupService
+	Common               *CommonService
+	Backup               *BackupService
+	Subaccount           *SubaccountService
+	Monitoring           *MonitoringService
+	SystemMapping        *SystemMappingService
+	ServiceChannel       *ServiceChannelService
+	HA                   *HAService
+	Authentication       *AuthenticationService
+	PrincipalPropagation *PrincipalPropagationService
+	Log                  *LogService
+	Certificate          *CertificateService
 }
 
 type service struct {
@@ -62,7 +120,14 @@ func (c *Client) Client() *http.Client {
 // provided, a new http.Client will be used. To use API methods which require
 // authentication, provide an http.Client that will perform the authentication
 // for you (such as that provided by the golang.org/x/oauth2 library).
-func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
+//
+// Unless httpClient has its own CheckRedirect policy, redirects are not
+// followed, as following them could send credentials to another host. See
+// WithSafeRedirects.
+//
+// Additional behavior can be configured by passing ClientOptions, which are
+// applied in order once the client has been set up.
+func NewClient(baseURL string, httpClient *http.Client, opts ...ClientOption) (*Client, error) {
 	baseEndpoint, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
@@ -70,30 +135,211 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
+	if httpClient.CheckRedirect == nil {
+		// Redirects are not followed unless enabled with WithSafeRedirects.
+		clientCopy := *httpClient
+		clientCopy.CheckRedirect = noRedirects
+		httpClient = &clientCopy
+	}
 
-	c := &Client{client: httpClient, BaseURL: baseEndpoint, UserAgent: userAgent}
+	c := &Client{client: httpClient, BaseURL: baseEndpoint, UserAgent: userAgent, retry: newRetryConfig(), dial: newDialConfig(), maxResponseBytes: defaultMaxResponseBytes}
 	c.common.client = c
 	c.Common = (*CommonService)(&c.common)
 	c.Backup = (*BackupService)(&c.common)
+	c.Subaccount = (*SubaccountService)(&c.common)
+	c.Monitoring = (*MonitoringService)(&c.common)
+	c.SystemMapping = (*SystemMappingService)(&c.common)
+	c.ServiceChannel = (*ServiceChannelService)(&c.common)
+	c.HA = (*HAService)(&c.common)
+	c.Authentication = (*AuthenticationService)(&c.common)
+	c.PrincipalPropagation = (*PrincipalPropagationService)(&c.common)
+	c.Log = (*LogService)(&c.common)
+	c.Certificate = (*CertificateService)(&c.common)
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
 	return c, nil
 }
 
+// A ClientOption configures optional behavior of a Client created by NewClient.
+type ClientOption func(*Client) error
+
+// WithShadowURL sets the base URL of the shadow instance of a high
+// availability setup. It is required by the methods that talk to the shadow
+// instance, such as HAService.ChangeShadowState. The shadow is reached with
+// the same HTTP client, and thus the same credentials, as the master.
+func WithShadowURL(shadowURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(shadowURL)
+		if err != nil {
+			return err
+		}
+		c.ShadowURL = u
+		return nil
+	}
+}
+
+// WithMaxResponseBytes limits the size of response bodies that Do reads into
+// memory to n bytes; larger responses fail with ErrResponseTooLarge. The
+// default limit is 32 MiB. Bodies streamed to an io.Writer, such as backups
+// and log archives, are not limited.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid response size limit %d", n)
+		}
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
+// WithDefaultRegionHost sets the region host used by subaccount methods that
+// are called with an empty region host, which streamlines clients managing
+// subaccounts of a single region. An explicitly passed region host always
+// takes precedence.
+func WithDefaultRegionHost(regionHost string) ClientOption {
+	return func(c *Client) error {
+		if err := ValidateRegionHost(regionHost); err != nil {
+			return err
+		}
+		c.defaultRegionHost = regionHost
+		return nil
+	}
+}
+
+// WithStrictJSON makes Do fail with a decode error when a JSON response
+// contains fields the target type does not know about. This helps detecting
+// API changes after a connector upgrade early. By default unknown fields are
+// ignored for forward compatibility.
+func WithStrictJSON() ClientOption {
+	return func(c *Client) error {
+		c.strictJSON = true
+		return nil
+	}
+}
+
+// WithSafeRedirects makes the client follow up to maxHops redirects, e.g. from
+// a load balancer in front of Cloud Connector, as long as they stay on the
+// same scheme and host as the original request. Redirects elsewhere are not
+// followed and the redirect response is returned instead, so credentials are
+// never sent to another host.
+func WithSafeRedirects(maxHops int) ClientOption {
+	return func(c *Client) error {
+		if maxHops < 0 {
+			return fmt.Errorf("invalid number of redirect hops %d", maxHops)
+		}
+		c.clientMu.Lock()
+		defer c.clientMu.Unlock()
+		clientCopy := *c.client
+		clientCopy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxHops {
+				return fmt.Errorf("stopped after %d redirects", maxHops)
+			}
+			if req.URL.Scheme != via[0].URL.Scheme || req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+		c.client = &clientCopy
+		return nil
+	}
+}
+
+func noRedirects(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// withTransport replaces the transport of c's HTTP client with the result of
+// wrap, which receives the current transport. The HTTP client is copied first
+// so that an http.Client supplied by the caller is not modified.
+func (c *Client) withTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.clientMu.Lock()
+	defer c.clientMu.Unlock()
+	clientCopy := *c.client
+	transport := clientCopy.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	clientCopy.Transport = wrap(transport)
+	c.client = &clientCopy
+}
+
+// Close closes the idle connections of the client's transport and marks the
+// client as closed; requests made afterwards fail with ErrClientClosed.
+//
+// Calling Close is optional when the client shares http.DefaultTransport with
+// the rest of the program, but releases resources when each client owns its
+// transport, e.g. because it was configured with WithKeepAlive.
+func (c *Client) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	c.clientMu.Lock()
+	defer c.clientMu.Unlock()
+	c.client.CloseIdleConnections()
+	return nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash. If
 // specified, the value pointed to by body is JSON encoded and included as the
-// request body.
+// request body. A string or []byte body is sent as is, as text/plain or
+// application/octet-stream respectively, for endpoints that do not take JSON.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
 	if !strings.HasSuffix(c.BaseURL.Path, "/") {
 		return nil, fmt.Errorf("BaseURL must have a trailing slash, but %q does not", c.BaseURL)
 	}
-	u, err := c.BaseURL.Parse(urlStr)
+	return c.newRequest(c.BaseURL, method, urlStr, body)
+}
+
+// NewShadowRequest creates an API request to the shadow instance. It works
+// like NewRequest, but urlStr is resolved relative to the ShadowURL of the
+// Client, which must have been configured with WithShadowURL.
+func (c *Client) NewShadowRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	if c.ShadowURL == nil {
+		return nil, ErrNoShadowURL
+	}
+	if !strings.HasSuffix(c.ShadowURL.Path, "/") {
+		return nil, fmt.Errorf("ShadowURL must have a trailing slash, but %q does not", c.ShadowURL)
+	}
+	req, err := c.newRequest(c.ShadowURL, method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithContext(context.WithValue(req.Context(), shadowRequestKey{}, true)), nil
+}
+
+// shadowRequestKey marks the context of requests built by NewShadowRequest.
+type shadowRequestKey struct{}
+
+// requestInstance returns the instance a request built by NewRequest or
+// NewShadowRequest is addressed to.
+func requestInstance(req *http.Request) string {
+	if shadow, _ := req.Context().Value(shadowRequestKey{}).(bool); shadow {
+		return HARoleShadow
+	}
+	return HARoleMaster
+}
+
+func (c *Client) newRequest(baseURL *url.URL, method, urlStr string, body interface{}) (*http.Request, error) {
+	u, err := baseURL.Parse(urlStr)
 	if err != nil {
 		return nil, err
 	}
 
 	var buf io.ReadWriter
-	if body != nil {
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case string:
+		buf = bytes.NewBufferString(b)
+		contentType = "text/plain; charset=utf-8"
+	case []byte:
+		buf = bytes.NewBuffer(b)
+		contentType = "application/octet-stream"
+	default:
 		buf = &bytes.Buffer{}
 		enc := json.NewEncoder(buf)
 		enc.SetEscapeHTML(false)
@@ -109,7 +355,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	}
 
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	if c.UserAgent != "" {
@@ -147,33 +393,136 @@ func (c *Client) NewUploadRequest(method, urlStr string, reader io.Reader, size
 // Response is a SCC API response.
 type Response struct {
 	*http.Response
+
+	// Attempts is the number of times the request was sent, which is greater
+	// than one if it was retried.
+	Attempts int
+
+	// TLSInfo describes the TLS connection the response was received on. It is
+	// nil for plaintext HTTP.
+	TLSInfo *TLSInfo
+
+	// Instance is the high availability instance the request was sent to,
+	// HARoleShadow for requests built with NewShadowRequest and HARoleMaster
+	// otherwise.
+	Instance string
+
+	// Warnings holds the warnings Cloud Connector reported alongside a
+	// successful result, e.g. that a created system mapping is unreachable.
+	// It is populated by Do.
+	Warnings []string
+}
+
+// TLSInfo describes the TLS connection negotiated with Cloud Connector.
+type TLSInfo struct {
+	Version          uint16              // TLS version, e.g. tls.VersionTLS12
+	VersionName      string              // TLS version in human-readable form, e.g. "TLS 1.2"
+	CipherSuite      uint16              // cipher suite, e.g. tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+	CipherSuiteName  string              // name of the cipher suite
+	PeerCertificates []*x509.Certificate // certificate chain presented by the connector
 }
 
 // newResponse creates a new Response for the provided http.Response.
 // r must not be nil.
 func newResponse(r *http.Response) *Response {
 	response := &Response{Response: r}
+	response.populateTLSInfo()
 	return response
 }
 
+// populateTLSInfo sets r.TLSInfo from the connection state of the underlying
+// http.Response.
+func (r *Response) populateTLSInfo() {
+	state := r.Response.TLS
+	if state == nil {
+		return
+	}
+	r.TLSInfo = &TLSInfo{
+		Version:          state.Version,
+		VersionName:      tlsVersionName(state.Version),
+		CipherSuite:      state.CipherSuite,
+		CipherSuiteName:  tls.CipherSuiteName(state.CipherSuite),
+		PeerCertificates: state.PeerCertificates,
+	}
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
+
 // BareDo sends an API request and lets you handle the api response. If an error
 // or API Error occurs, the error will contain more information. Otherwise you
 // are supposed to read and close the response's Body.
 //
+// If retries are enabled with WithRetry, requests failing with a network error
+// or a retryable status code are sent again.
+//
 // The provided ctx must be non-nil, if it is nil an error is returned. If it is
-// canceled or times out, ctx.Err() will be returned.
+// canceled or times out, ctx.Err() will be returned wrapped in a *RequestError.
 func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, error) {
 	if ctx == nil {
 		return nil, errNonNilContext
 	}
+	if atomic.LoadInt32(&c.closed) != 0 {
+		return nil, ErrClientClosed
+	}
+	instance := requestInstance(req)
 	req = req.WithContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		response, err := c.bareDo(ctx, req)
+		if response != nil {
+			response.Attempts = attempt + 1
+			response.Instance = instance
+		}
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			reqErr.Attempts = attempt + 1
+		}
+		if !c.shouldRetry(ctx, req, attempt, response, err) {
+			return response, err
+		}
+		if response != nil {
+			response.Body.Close()
+		}
+		if err := c.waitRetry(ctx, req, attempt, response, err); err != nil {
+			return nil, &RequestError{Method: req.Method, Path: req.URL.Path, Err: err, Attempts: attempt + 1}
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// bareDo performs a single attempt of BareDo.
+func (c *Client) bareDo(ctx context.Context, req *http.Request) (*Response, error) {
+	if plan := dryRunPlan(ctx); plan != nil && isMutating(req.Method) {
+		resp, err := plan.record(req)
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(resp), nil
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, &RequestError{Method: req.Method, Path: req.URL.Path, Err: ctx.Err()}
 		default:
 		}
 
@@ -181,11 +530,11 @@ func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, erro
 		if e, ok := err.(*url.Error); ok {
 			if url, err := url.Parse(e.URL); err == nil {
 				e.URL = sanitizeURL(url).String()
-				return nil, e
+				return nil, &RequestError{Method: req.Method, Path: req.URL.Path, Err: e}
 			}
 		}
 
-		return nil, err
+		return nil, &RequestError{Method: req.Method, Path: req.URL.Path, Err: err}
 	}
 
 	response := newResponse(resp)
@@ -200,31 +549,154 @@ func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, erro
 // error if an API error has occurred. If v implements the io.Writer interface,
 // the raw response body will be written to v, without attempting to first
 // decode it. If v is nil, and no error hapens, the response is returned as is.
+// An empty response body, as returned by some setters, is not an error and
+// leaves v unchanged. Bodies that are not written to an io.Writer are limited
+// in size, see WithMaxResponseBytes. Any warnings reported in a JSON response
+// body are stored in the returned Response.
 //
 // The provided ctx must be non-nil, if it is nil an error is returned. If it
-// is canceled or times out, ctx.Err() will be returned.
+// is canceled or times out, ctx.Err() will be returned wrapped in a
+// *RequestError.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
 	resp, err := c.BareDo(ctx, req)
 	if err != nil {
 		return resp, err
 	}
+	return resp, c.decodeResponse(resp, v)
+}
+
+// DoRaw works like Do but also returns the raw response body, e.g. to inspect
+// fields that v does not capture. The body is copied while it is decoded, so
+// no additional request is made. If v is an io.Writer, the body is both
+// written to v and returned. Otherwise, including when v is nil, the body is
+// limited in size like in Do, which makes DoRaw(ctx, req, nil) suited to read
+// small text bodies; use an io.Writer only for large downloads.
+func (c *Client) DoRaw(ctx context.Context, req *http.Request, v interface{}) ([]byte, *Response, error) {
+	resp, err := c.BareDo(ctx, req)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var raw bytes.Buffer
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(resp.Body, &raw), resp.Body}
+	err = c.decodeResponse(resp, v)
+	return raw.Bytes(), resp, err
+}
+
+// decodeResponse reads the body of resp into v as described for Do and closes
+// it.
+func (c *Client) decodeResponse(resp *Response, v interface{}) (err error) {
 	defer resp.Body.Close()
 
-	switch v := v.(type) {
-	case nil:
-	case io.Writer:
-		_, err = io.Copy(v, resp.Body)
-	default:
-		decErr := json.NewDecoder(resp.Body).Decode(v)
-		if decErr == io.EOF {
-			decErr = nil // ignore EOF errors caused by empty response body
+	if w, ok := v.(io.Writer); ok {
+		_, err = io.Copy(w, resp.Body)
+		return err
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > c.maxResponseBytes {
+		return ErrResponseTooLarge
+	}
+	resp.Warnings = parseWarnings(data)
+	if v != nil && len(bytes.TrimSpace(data)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if c.strictJSON {
+			dec.DisallowUnknownFields()
 		}
-		if decErr != nil {
-			err = decErr
+		err = dec.Decode(v)
+	}
+
+	return err
+}
+
+// trimTextBody returns the body of a text endpoint without surrounding
+// whitespace and one layer of surrounding double quotes, which some connector
+// versions add.
+func trimTextBody(data []byte) string {
+	text := strings.TrimSpace(string(data))
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	return text
+}
+
+// DoAsync works like Do for operations that Cloud Connector may run
+// asynchronously. If the API answers with 202 Accepted and a Location header,
+// the Location is polled with GET requests every interval until it reports a
+// status other than 202, and that final response is decoded into v instead.
+func (c *Client) DoAsync(ctx context.Context, req *http.Request, v interface{}, interval time.Duration) (*Response, error) {
+	resp, err := c.BareDo(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	for resp.StatusCode == http.StatusAccepted {
+		location := resp.Header.Get("Location")
+		if location == "" {
+			break
 		}
+		u, err := resp.Request.URL.Parse(location)
+		if err != nil {
+			resp.Body.Close()
+			return resp, err
+		}
+		resp.Body.Close()
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return resp, &RequestError{Method: "GET", Path: u.Path, Err: ctx.Err()}
+		case <-t.C:
+		}
+
+		pollReq, err := c.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return resp, err
+		}
+		if resp, err = c.BareDo(ctx, pollReq); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, c.decodeResponse(resp, v)
+}
+
+// parseWarnings extracts the warnings field of a JSON object response body.
+// Warnings may be reported as plain strings or as objects with a message.
+func parseWarnings(data []byte) []string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil
+	}
+	var body struct {
+		Warnings []json.RawMessage `json:"warnings"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
 	}
 
-	return resp, err
+	var warnings []string
+	for _, raw := range body.Warnings {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			warnings = append(warnings, text)
+			continue
+		}
+		var obj struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(raw, &obj); err == nil && obj.Message != "" {
+			warnings = append(warnings, obj.Message)
+		}
+	}
+	return warnings
 }
 
 /*
@@ -244,6 +716,70 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Type, r.Message)
 }
 
+// exists reports whether the resource at urlStr exists. It uses a HEAD request
+// and falls back to GET if the endpoint does not support HEAD.
+func (c *Client) exists(ctx context.Context, urlStr string) (bool, *Response, error) {
+	for _, method := range []string{"HEAD", "GET"} {
+		req, err := c.NewRequest(method, urlStr, nil)
+		if err != nil {
+			return false, nil, err
+		}
+
+		resp, err := c.Do(ctx, req, nil)
+		switch {
+		case err == nil:
+			return true, resp, nil
+		case isNotFound(err):
+			return false, resp, nil
+		case resp != nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented):
+			continue
+		default:
+			return false, resp, err
+		}
+	}
+	return false, nil, errors.New("existence check not supported by " + urlStr)
+}
+
+// isNotFound reports whether err is an API error with status 404 Not Found.
+func isNotFound(err error) bool {
+	var errResp *ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// A RequestError reports a request that failed before an API response was
+// received, e.g. because of a network error or a canceled context. It records
+// which endpoint was being called; the cause is available via errors.Unwrap.
+type RequestError struct {
+	Method string // HTTP method of the failed request
+	Path   string // URL path of the failed request
+	Err    error  // underlying error
+
+	// Attempts is the number of times the request was sent, like
+	// Response.Attempts.
+	Attempts int
+}
+
+func (r *RequestError) Error() string {
+	return fmt.Sprintf("%v %v: %v", r.Method, r.Path, r.Err)
+}
+
+func (r *RequestError) Unwrap() error {
+	return r.Err
+}
+
+// requestAttempts returns the number of times a request was sent by BareDo,
+// given its results, or 0 if that is not known.
+func requestAttempts(resp *Response, err error) int {
+	if resp != nil {
+		return resp.Attempts
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Attempts
+	}
+	return 0
+}
+
 // sanitizeURL redacts the client_secret parameter from the URL which may be
 // exposed to the user.
 func sanitizeURL(uri *url.URL) *url.URL {
@@ -260,7 +796,8 @@ func sanitizeURL(uri *url.URL) *url.URL {
 
 // CheckResponse checks the API response for errors, and returns them if
 // present. A response is considered an error if it has a status code outside
-// the 200 range or equal to 202 Accepted.
+// the 200 range. Asynchronous operations answering with 202 Accepted can be
+// followed with DoAsync.
 // API error responses are expected to have response
 // body, and a JSON response body that maps to ErrorResponse.
 func CheckResponse(r *http.Response) error {
@@ -305,20 +842,80 @@ type BasicAuthTransport struct {
 	// Transport is the underlying HTTP transport to use when making requests.
 	// It will default to http.DefaultTransport if nil.
 	Transport http.RoundTripper
+
+	mu sync.RWMutex // mu protects Username and Password once requests are made.
 }
 
 // RoundTrip implements the RoundTripper interface.
 func (t *BasicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req2 := setCredentialsAsHeaders(req, t.Username, t.Password)
+	username, password := t.credentials()
+	req2 := setCredentialsAsHeaders(req, username, password)
 	return t.transport().RoundTrip(req2)
 }
 
+func (t *BasicAuthTransport) credentials() (username, password string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.Username, t.Password
+}
+
+// setPassword replaces the password used by requests made from now on.
+func (t *BasicAuthTransport) setPassword(password string) {
+	t.mu.Lock()
+	t.Password = password
+	t.mu.Unlock()
+}
+
+// CloseIdleConnections closes the idle connections of the underlying
+// transport.
+func (t *BasicAuthTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport())
+}
+
 // Client returns an *http.Client that makes requests that are authenticated
 // using HTTP Basic Authentication.
 func (t *BasicAuthTransport) Client() *http.Client {
 	return &http.Client{Transport: t}
 }
 
+// closeIdleConnections closes the idle connections of rt if it supports it.
+func closeIdleConnections(rt http.RoundTripper) {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+	if ci, ok := rt.(closeIdler); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+// transportWrapper is implemented by the transports of this package that
+// delegate to another transport.
+type transportWrapper interface {
+	unwrap() http.RoundTripper
+
+	// rewrap returns a copy of the wrapper delegating to inner instead.
+	rewrap(inner http.RoundTripper) http.RoundTripper
+}
+
+// basicAuthTransport returns the BasicAuthTransport used by c, looking through
+// any transports of this package wrapping it, or nil if there is none.
+func (c *Client) basicAuthTransport() *BasicAuthTransport {
+	c.clientMu.Lock()
+	rt := c.client.Transport
+	c.clientMu.Unlock()
+	for rt != nil {
+		switch t := rt.(type) {
+		case *BasicAuthTransport:
+			return t
+		case transportWrapper:
+			rt = t.unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
 func (t *BasicAuthTransport) transport() http.RoundTripper {
 	if t.Transport != nil {
 		return t.Transport
@@ -0,0 +1,110 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// setup starts a test server serving mux and returns a client talking to it.
+// The server is closed when the test ends.
+func setup(t *testing.T, opts ...ClientOption) (*Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL+"/", nil, opts...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return client, mux
+}
+
+func TestDo_emptyBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"content length 0", ""},
+		{"whitespace", "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mux := setup(t)
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+				fmt.Fprint(w, tt.body)
+			})
+
+			req, err := client.NewRequest("GET", ".", nil)
+			if err != nil {
+				t.Fatalf("NewRequest returned error: %v", err)
+			}
+			v := &Version{Version: "unchanged"}
+			resp, err := client.Do(context.Background(), req, v)
+			if err != nil {
+				t.Fatalf("Do returned error: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Do returned status %v, want %v", resp.StatusCode, http.StatusOK)
+			}
+			if v.Version != "unchanged" {
+				t.Errorf("Do modified v to %+v", v)
+			}
+		})
+	}
+}
+
+func TestNewRequest_body(t *testing.T) {
+	client, _ := setup(t)
+	tests := []struct {
+		body        interface{}
+		contentType string
+		want        string
+	}{
+		{"master", "text/plain; charset=utf-8", "master"},
+		{[]byte{0x50, 0x4b, 0x03, 0x04}, "application/octet-stream", "PK\x03\x04"},
+		{struct {
+			Role string `json:"role"`
+		}{"master"}, "application/json", `{"role":"master"}` + "\n"},
+	}
+	for _, tt := range tests {
+		req, err := client.NewRequest("PUT", "x", tt.body)
+		if err != nil {
+			t.Fatalf("NewRequest returned error: %v", err)
+		}
+		if got := req.Header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("NewRequest(%#v) Content-Type = %q, want %q", tt.body, got, tt.contentType)
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != tt.want {
+			t.Errorf("NewRequest(%#v) body = %q, want %q", tt.body, body, tt.want)
+		}
+	}
+}
+
+func TestTrimTextBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"master", "master"},
+		{"master\n", "master"},
+		{`"master"`, "master"},
+		{"\" master \"\r\n", "master"},
+		{`"`, `"`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := trimTextBody([]byte(tt.body)); got != tt.want {
+			t.Errorf("trimTextBody(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
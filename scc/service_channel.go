@@ -0,0 +1,249 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+)
+
+type ServiceChannelService service
+
+// ChannelType is the type of a service channel.
+type ChannelType string
+
+// Service channel types, used as the channelType argument of the
+// ServiceChannelService methods.
+const (
+	ChannelTypeHANADatabase   ChannelType = "HANADB"
+	ChannelTypeVirtualMachine ChannelType = "VirtualMachine"
+	ChannelTypeK8SCluster     ChannelType = "K8SCluster"
+	ChannelTypeRFC            ChannelType = "RFC"
+	ChannelTypeLDAP           ChannelType = "LDAP"
+)
+
+// channelTypes lists all known service channel types.
+var channelTypes = []ChannelType{
+	ChannelTypeHANADatabase,
+	ChannelTypeVirtualMachine,
+	ChannelTypeK8SCluster,
+	ChannelTypeRFC,
+	ChannelTypeLDAP,
+}
+
+// ChannelTypes returns all known service channel types.
+func ChannelTypes() []ChannelType {
+	return append([]ChannelType(nil), channelTypes...)
+}
+
+// Valid reports whether t is a known service channel type.
+func (t ChannelType) Valid() bool {
+	for _, known := range channelTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func validateChannelType(t ChannelType) error {
+	if !t.Valid() {
+		return fmt.Errorf("invalid service channel type %q", t)
+	}
+	return nil
+}
+
+// ServiceChannel gives cloud services on-premise access to a resource, such
+// as a HANA database or a virtual machine, through a subaccount tunnel.
+type ServiceChannel struct {
+	ID          int    `json:"id"`
+	TypeKey     string `json:"typeKey"`
+	Details     string `json:"details"`
+	ServiceNo   int    `json:"serviceNumber"`
+	Connections int    `json:"connections"`
+	Enabled     bool   `json:"enabled"`
+	Comment     string `json:"comment,omitempty"`
+	State       *struct {
+		Connected         bool `json:"connected"`
+		OpenedConnections int  `json:"openedConnections"`
+	} `json:"state,omitempty"`
+}
+
+// ListServiceChannels lists the service channels of the given type of a subaccount
+func (s *ServiceChannelService) ListServiceChannels(ctx context.Context, regionHost, subaccount string, channelType ChannelType) ([]*ServiceChannel, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+	if err := validateChannelType(channelType); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/channels/%v", regionHost, subaccount, channelType)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var channels []*ServiceChannel
+	resp, err := s.client.Do(ctx, req, &channels)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return channels, resp, nil
+}
+
+// CreateServiceChannel creates a service channel of the given type for a subaccount
+func (s *ServiceChannelService) CreateServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, channel *ServiceChannel) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+	if err := validateChannelType(channelType); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/channels/%v", regionHost, subaccount, channelType)
+	req, err := s.client.NewRequest("POST", u, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// UpdateServiceChannel updates the service channel of a subaccount identified
+// by the type and ID of channel
+func (s *ServiceChannelService) UpdateServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, channel *ServiceChannel) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+	if err := validateChannelType(channelType); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/channels/%v/%v", regionHost, subaccount, channelType, channel.ID)
+	req, err := s.client.NewRequest("PUT", u, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// DeleteServiceChannel deletes a service channel of a subaccount
+func (s *ServiceChannelService) DeleteServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+	if err := validateChannelType(channelType); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/channels/%v/%v", regionHost, subaccount, channelType, id)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// GetServiceChannel gets a service channel of a subaccount
+func (s *ServiceChannelService) GetServiceChannel(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int) (*ServiceChannel, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+	if err := validateChannelType(channelType); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/channels/%v/%v", regionHost, subaccount, channelType, id)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	channel := new(ServiceChannel)
+	resp, err := s.client.Do(ctx, req, channel)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return channel, resp, nil
+}
+
+// Range of connections SetChannelConnections accepts. The bounds are checked
+// by this package before any request is made; they are not read from the
+// connector, which may reject counts within the range for some channel types.
+const (
+	MinChannelConnections = 1
+	MaxChannelConnections = 100
+)
+
+// SetChannelConnections sets the maximum number of connections of a service
+// channel, leaving its other settings unchanged. The channel is read first and
+// written back with the new count; the returned channel is read again
+// afterwards and holds the effective settings. The Response is the one of the
+// update.
+func (s *ServiceChannelService) SetChannelConnections(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id, count int) (*ServiceChannel, *Response, error) {
+	if count < MinChannelConnections || count > MaxChannelConnections {
+		return nil, nil, fmt.Errorf("connection count %d out of range [%d, %d]", count, MinChannelConnections, MaxChannelConnections)
+	}
+
+	channel, resp, err := s.GetServiceChannel(ctx, regionHost, subaccount, channelType, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	channel.Connections = count
+	channel.State = nil
+	resp, err = s.UpdateServiceChannel(ctx, regionHost, subaccount, channelType, channel)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	updated, _, err := s.GetServiceChannel(ctx, regionHost, subaccount, channelType, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
+
+// ListAllServiceChannels lists the service channels of all types of a
+// subaccount, keyed by type. Types without channels are omitted.
+func (s *ServiceChannelService) ListAllServiceChannels(ctx context.Context, regionHost, subaccount string) (map[ChannelType][]*ServiceChannel, error) {
+	all := make(map[ChannelType][]*ServiceChannel)
+	for _, channelType := range channelTypes {
+		channels, _, err := s.ListServiceChannels(ctx, regionHost, subaccount, channelType)
+		if err != nil {
+			return nil, err
+		}
+		if len(channels) > 0 {
+			all[channelType] = channels
+		}
+	}
+	return all, nil
+}
+
+// SetServiceChannelEnabled enables or disables a service channel, leaving its
+// other settings unchanged. Like SetChannelConnections it returns the channel
+// as read after the update and the Response of the update.
+func (s *ServiceChannelService) SetServiceChannelEnabled(ctx context.Context, regionHost, subaccount string, channelType ChannelType, id int, enabled bool) (*ServiceChannel, *Response, error) {
+	channel, resp, err := s.GetServiceChannel(ctx, regionHost, subaccount, channelType, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	channel.Enabled = enabled
+	channel.State = nil
+	resp, err = s.UpdateServiceChannel(ctx, regionHost, subaccount, channelType, channel)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	updated, _, err := s.GetServiceChannel(ctx, regionHost, subaccount, channelType, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
@@ -0,0 +1,78 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ShadowConfigSummary is the configuration held by the shadow instance, which
+// it activates when it takes over from the master.
+type ShadowConfigSummary struct {
+	Subaccounts []ShadowSubaccountSummary
+}
+
+// ShadowSubaccountSummary summarizes a subaccount held by the shadow instance.
+type ShadowSubaccountSummary struct {
+	RegionHost     string
+	Subaccount     string
+	LocationID     string
+	DisplayName    string
+	SystemMappings int // number of system mappings
+}
+
+// GetShadowConfigSummary reads the subaccounts and system mappings held by the
+// shadow instance, i.e. what would become active after a takeover. Comparing
+// it with the master's configuration before switching roles avoids surprises
+// after a failover. Subaccounts whose system mappings cannot be read are
+// still listed, with a SystemMappings count of 0, and their errors are
+// returned alongside the summary in a *MultiError keyed by subaccount. It
+// requires a shadow URL configured with WithShadowURL.
+func (s *HAService) GetShadowConfigSummary(ctx context.Context) (*ShadowConfigSummary, error) {
+	req, err := s.client.NewShadowRequest("GET", "api/v1/configuration/subaccounts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var subaccounts []*Subaccount
+	if _, err := s.client.Do(ctx, req, &subaccounts); err != nil {
+		return nil, err
+	}
+
+	summary := &ShadowConfigSummary{Subaccounts: make([]ShadowSubaccountSummary, len(subaccounts))}
+	results := make(map[SubaccountKey]error, len(subaccounts))
+	var mu sync.Mutex
+	forEach(len(subaccounts), defaultConcurrency, func(i int) {
+		sa := subaccounts[i]
+		summary.Subaccounts[i] = ShadowSubaccountSummary{
+			RegionHost:  sa.RegionHost,
+			Subaccount:  sa.Subaccount,
+			LocationID:  sa.LocationID,
+			DisplayName: sa.DisplayName,
+		}
+		count, err := s.countShadowSystemMappings(ctx, sa.RegionHost, sa.Subaccount)
+		summary.Subaccounts[i].SystemMappings = count
+
+		mu.Lock()
+		results[SubaccountKey{RegionHost: sa.RegionHost, Subaccount: sa.Subaccount}] = err
+		mu.Unlock()
+	})
+
+	return summary, subaccountErrors(results)
+}
+
+// countShadowSystemMappings returns the number of system mappings of a
+// subaccount held by the shadow instance.
+func (s *HAService) countShadowSystemMappings(ctx context.Context, regionHost, subaccount string) (int, error) {
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings", regionHost, subaccount)
+	req, err := s.client.NewShadowRequest("GET", u, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var mappings []*SystemMapping
+	if _, err := s.client.Do(ctx, req, &mappings); err != nil {
+		return 0, err
+	}
+	return len(mappings), nil
+}
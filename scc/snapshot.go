@@ -0,0 +1,64 @@
+package scc
+
+import "context"
+
+// SnapshotOptions configures Snapshot and RestoreSnapshot.
+type SnapshotOptions struct {
+	// IncludeSecrets keeps secrets, such as the cloud passwords of
+	// subaccounts, in the snapshot. By default they are removed. Cloud
+	// Connector never returns secrets, so they are only present if the caller
+	// added them to the configuration.
+	IncludeSecrets bool
+}
+
+// Snapshot takes a plaintext snapshot of the connector configuration. It is
+// an alternative to BackupService for environments that cannot manage backup
+// passwords: the snapshot can be reviewed, diffed and merged, but it does not
+// contain secrets unless explicitly requested with opts, and restoring it does
+// not restore anything ExportConfig does not cover.
+func (c *Client) Snapshot(ctx context.Context, opts *SnapshotOptions) (*ConnectorConfig, error) {
+	config, err := c.ExportConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || !opts.IncludeSecrets {
+		config = config.WithoutSecrets()
+	}
+	return config, nil
+}
+
+// RestoreSnapshot applies a snapshot taken with Snapshot using ImportConfig.
+// Secrets in the snapshot are only sent to the connector if opts requests it.
+func (c *Client) RestoreSnapshot(ctx context.Context, config *ConnectorConfig, opts *SnapshotOptions) error {
+	if err := validateSubaccountConfigs(config.Subaccounts); err != nil {
+		return err
+	}
+	if opts == nil || !opts.IncludeSecrets {
+		config = config.WithoutSecrets()
+	}
+	return c.ImportConfig(ctx, config)
+}
+
+// WithoutSecrets returns a copy of c with all secrets removed. Use it before
+// logging or persisting a configuration that might hold secrets. Nil
+// subaccount entries are kept as they are; a nil c yields nil.
+func (c *ConnectorConfig) WithoutSecrets() *ConnectorConfig {
+	if c == nil {
+		return nil
+	}
+	configCopy := *c
+	configCopy.Subaccounts = make([]*SubaccountConfig, len(c.Subaccounts))
+	for i, sa := range c.Subaccounts {
+		if sa == nil {
+			continue
+		}
+		saConfigCopy := *sa
+		if sa.Subaccount != nil {
+			saCopy := *sa.Subaccount
+			saCopy.CloudPassword = ""
+			saConfigCopy.Subaccount = &saCopy
+		}
+		configCopy.Subaccounts[i] = &saConfigCopy
+	}
+	return &configCopy
+}
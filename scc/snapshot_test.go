@@ -0,0 +1,33 @@
+package scc
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConnectorConfig_WithoutSecrets_nil(t *testing.T) {
+	var config *ConnectorConfig
+	if got := config.WithoutSecrets(); got != nil {
+		t.Errorf("WithoutSecrets of nil config returned %+v, want nil", got)
+	}
+
+	config = &ConnectorConfig{Subaccounts: []*SubaccountConfig{nil, {}}}
+	got := config.WithoutSecrets()
+	if len(got.Subaccounts) != 2 || got.Subaccounts[0] != nil || got.Subaccounts[1] == nil {
+		t.Errorf("WithoutSecrets returned subaccounts %+v", got.Subaccounts)
+	}
+}
+
+func TestClient_RestoreSnapshot_noSubaccount(t *testing.T) {
+	client, _ := setup(t)
+	for _, config := range []*ConnectorConfig{
+		{Subaccounts: []*SubaccountConfig{nil}},
+		{Subaccounts: []*SubaccountConfig{{}}},
+	} {
+		err := client.RestoreSnapshot(context.Background(), config, nil)
+		if err == nil || !strings.Contains(err.Error(), "has no subaccount") {
+			t.Errorf("RestoreSnapshot returned error %v, want a missing subaccount error", err)
+		}
+	}
+}
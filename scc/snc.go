@@ -0,0 +1,73 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SNCQoP is the quality of protection of an SNC (Secure Network
+// Communication) secured RFC connection.
+type SNCQoP int
+
+// Qualities of protection supported by SNC.
+const (
+	SNCQoPAuthentication SNCQoP = 1 // authentication only
+	SNCQoPIntegrity      SNCQoP = 2 // integrity protection
+	SNCQoPPrivacy        SNCQoP = 3 // privacy protection (encryption)
+	SNCQoPDefault        SNCQoP = 8 // default protection of the SNC library
+	SNCQoPMaximum        SNCQoP = 9 // maximum protection available
+)
+
+// Valid reports whether q is a quality of protection supported by SNC.
+func (q SNCQoP) Valid() bool {
+	switch q {
+	case SNCQoPAuthentication, SNCQoPIntegrity, SNCQoPPrivacy, SNCQoPDefault, SNCQoPMaximum:
+		return true
+	}
+	return false
+}
+
+// SNCSettings are the SNC settings of an RFC system mapping.
+type SNCSettings struct {
+	PartnerName string // SNC name of the backend system, e.g. p:CN=ABC
+	QoP         SNCQoP
+}
+
+// GetSystemMappingSNC gets the SNC settings of an RFC system mapping
+func (s *SystemMappingService) GetSystemMappingSNC(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SNCSettings, *Response, error) {
+	mapping, resp, err := s.GetSystemMapping(ctx, regionHost, subaccount, virtualHost, virtualPort)
+	if err != nil {
+		return nil, resp, err
+	}
+	if !isRFCProtocol(mapping.Protocol) {
+		return nil, resp, fmt.Errorf("system mapping %v:%v uses protocol %v, SNC requires RFC", virtualHost, virtualPort, mapping.Protocol)
+	}
+
+	return &SNCSettings{PartnerName: mapping.SNCPartnerName, QoP: mapping.SNCQoP}, resp, nil
+}
+
+// SetSystemMappingSNC sets the SNC settings of an RFC system mapping, leaving
+// its other settings unchanged.
+func (s *SystemMappingService) SetSystemMappingSNC(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, settings SNCSettings) (*Response, error) {
+	if !settings.QoP.Valid() {
+		return nil, fmt.Errorf("invalid SNC quality of protection %d", settings.QoP)
+	}
+
+	mapping, resp, err := s.GetSystemMapping(ctx, regionHost, subaccount, virtualHost, virtualPort)
+	if err != nil {
+		return resp, err
+	}
+	if !isRFCProtocol(mapping.Protocol) {
+		return resp, fmt.Errorf("system mapping %v:%v uses protocol %v, SNC requires RFC", virtualHost, virtualPort, mapping.Protocol)
+	}
+
+	mapping.SNCPartnerName = settings.PartnerName
+	mapping.SNCQoP = settings.QoP
+	return s.UpdateSystemMapping(ctx, regionHost, subaccount, mapping)
+}
+
+// isRFCProtocol reports whether protocol is RFC or its TLS variant RFCS.
+func isRFCProtocol(protocol string) bool {
+	return strings.EqualFold(protocol, "RFC") || strings.EqualFold(protocol, "RFCS")
+}
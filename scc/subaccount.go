@@ -0,0 +1,524 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type SubaccountService service
+
+// Tunnel states reported by Cloud Connector for a subaccount.
+const (
+	TunnelStateConnected      = "Connected"
+	TunnelStateConnectFailure = "ConnectFailure"
+	TunnelStateDisconnected   = "Disconnected"
+)
+
+type Subaccount struct {
+	RegionHost  string `json:"regionHost"`
+	Subaccount  string `json:"subaccount"`
+	LocationID  string `json:"locationID,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// CloudUser and CloudPassword are the credentials used when adding a
+	// subaccount. They are never returned by Cloud Connector.
+	CloudUser     string `json:"cloudUser,omitempty"`
+	CloudPassword string `json:"cloudPassword,omitempty"`
+
+	Tunnel *Tunnel `json:"tunnel,omitempty"`
+}
+
+type Tunnel struct {
+	State                  string                   `json:"state"`
+	ConnectedSince         string                   `json:"connectedSince,omitempty"`
+	Connections            int                      `json:"connections"`
+	User                   string                   `json:"user,omitempty"`
+	ApplicationConnections []*ApplicationConnection `json:"applicationConnections,omitempty"`
+	SubaccountCertificate  *SubaccountCertificate   `json:"subaccountCertificate,omitempty"`
+}
+
+// SubaccountCertificate is the certificate a subaccount tunnel authenticates
+// with.
+type SubaccountCertificate struct {
+	SubjectDN string    `json:"subjectDN"`
+	IssuerDN  string    `json:"issuerDN"`
+	NotBefore Timestamp `json:"notBeforeTimeStamp"`
+	NotAfter  Timestamp `json:"notAfterTimeStamp"`
+}
+
+// ApplicationConnection is a cloud application that routes through a
+// subaccount tunnel.
+type ApplicationConnection struct {
+	Name            string     `json:"applicationName"`
+	Type            string     `json:"type,omitempty"`
+	ConnectionCount int        `json:"connectionCount"`
+	LastUsed        *Timestamp `json:"lastUsed,omitempty"`
+}
+
+// ReconnectOptions configures the backoff used by EnsureSubaccountConnected.
+type ReconnectOptions struct {
+	// MaxAttempts is the maximum number of connect attempts. Zero means retry
+	// until the context expires.
+	MaxAttempts int
+
+	// InitialBackoff is the delay after the first failed attempt. Defaults to 1s.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Defaults to 1m.
+	MaxBackoff time.Duration
+}
+
+// GetSubaccount gets the configuration and tunnel state of a subaccount
+func (s *SubaccountService) GetSubaccount(ctx context.Context, regionHost, subaccount string) (*Subaccount, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sa := new(Subaccount)
+	resp, err := s.client.Do(ctx, req, sa)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return sa, resp, nil
+}
+
+// SubaccountExists reports whether a subaccount is configured in Cloud Connector
+func (s *SubaccountService) SubaccountExists(ctx context.Context, regionHost, subaccount string) (bool, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return false, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v", regionHost, subaccount)
+	return s.client.exists(ctx, u)
+}
+
+// AddSubaccount adds a subaccount to Cloud Connector and connects its tunnel.
+// RegionHost, Subaccount, CloudUser and CloudPassword of sa are required,
+// RegionHost can be omitted if configured with WithDefaultRegionHost.
+//
+// If the request was retried and finally failed, whatever the status, an
+// earlier attempt may have created the subaccount, so it is looked up and
+// returned if it exists.
+//
+// Cloud Connector ignores idempotency keys. A key supplied with
+// WithIdempotencyKey is nevertheless sent as Idempotency-Key header, for
+// proxies in front of the connector that de-duplicate requests; without one
+// no header is sent.
+func (s *SubaccountService) AddSubaccount(ctx context.Context, sa *Subaccount) (*Subaccount, *Response, error) {
+	regionHost := sa.RegionHost
+	if err := s.client.resolveSubaccountKey(&regionHost, sa.Subaccount); err != nil {
+		return nil, nil, err
+	}
+	if regionHost != sa.RegionHost {
+		withRegion := *sa
+		withRegion.RegionHost = regionHost
+		sa = &withRegion
+	}
+
+	req, err := s.client.NewRequest("POST", "api/v1/configuration/subaccounts", sa)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key, ok := idempotencyKey(ctx); ok {
+		req.Header.Set(headerIdempotencyKey, key)
+	}
+
+	created := new(Subaccount)
+	resp, err := s.client.Do(ctx, req, created)
+	if err != nil {
+		if requestAttempts(resp, err) > 1 {
+			if existing, getResp, getErr := s.GetSubaccount(ctx, sa.RegionHost, sa.Subaccount); getErr == nil {
+				return existing, getResp, nil
+			}
+		}
+		return nil, resp, err
+	}
+
+	return created, resp, nil
+}
+
+// ListApplicationConnections lists the cloud applications currently connected
+// through the tunnel of a subaccount, i.e. those affected if it is disconnected
+func (s *SubaccountService) ListApplicationConnections(ctx context.Context, regionHost, subaccount string) ([]*ApplicationConnection, *Response, error) {
+	sa, resp, err := s.GetSubaccount(ctx, regionHost, subaccount)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	connections := []*ApplicationConnection{}
+	if sa.Tunnel != nil && sa.Tunnel.ApplicationConnections != nil {
+		connections = sa.Tunnel.ApplicationConnections
+	}
+	return connections, resp, nil
+}
+
+// ConnectSubaccount connects the tunnel of a subaccount
+func (s *SubaccountService) ConnectSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error) {
+	return s.setConnected(ctx, regionHost, subaccount, true)
+}
+
+// DisconnectSubaccount disconnects the tunnel of a subaccount
+func (s *SubaccountService) DisconnectSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error) {
+	return s.setConnected(ctx, regionHost, subaccount, false)
+}
+
+func (s *SubaccountService) setConnected(ctx context.Context, regionHost, subaccount string, connected bool) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/state", regionHost, subaccount)
+	req, err := s.client.NewRequest("PUT", u, struct {
+		Connected bool `json:"connected"`
+	}{Connected: connected})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// EnsureSubaccountConnected checks the tunnel state of a subaccount and, if it
+// is not connected, issues connect requests with exponential backoff until the
+// tunnel is connected, the attempts are exhausted or ctx expires. A connect
+// request rejected with a client error that is not retryable, such as 401 or
+// 404, ends the attempts immediately. It returns the last observed tunnel
+// state, the number of connect attempts made and, if the tunnel did not
+// connect, an error wrapping the last connect error.
+func (s *SubaccountService) EnsureSubaccountConnected(ctx context.Context, regionHost, subaccount string, opts *ReconnectOptions) (string, int, error) {
+	if opts == nil {
+		opts = &ReconnectOptions{}
+	}
+	backoff := opts.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	maxBackoff := opts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	state := ""
+	attempts := 0
+	var connectErr error // error of the last connect request, if it failed
+	for {
+		sa, _, err := s.GetSubaccount(ctx, regionHost, subaccount)
+		if err != nil {
+			return state, attempts, err
+		}
+		if sa.Tunnel != nil {
+			state = sa.Tunnel.State
+		}
+		if state == TunnelStateConnected {
+			return state, attempts, nil
+		}
+		if opts.MaxAttempts > 0 && attempts >= opts.MaxAttempts {
+			if connectErr != nil {
+				return state, attempts, fmt.Errorf("subaccount %v/%v not connected after %d attempts, state %q: %w", regionHost, subaccount, attempts, state, connectErr)
+			}
+			return state, attempts, fmt.Errorf("subaccount %v/%v not connected after %d attempts, state %q", regionHost, subaccount, attempts, state)
+		}
+
+		if attempts > 0 {
+			t := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				if connectErr != nil {
+					return state, attempts, fmt.Errorf("subaccount %v/%v not connected (%v): %w", regionHost, subaccount, ctx.Err(), connectErr)
+				}
+				return state, attempts, ctx.Err()
+			case <-t.C:
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		attempts++
+		// A failed connect is retried on the next iteration unless the
+		// connector rejected the request itself; otherwise the tunnel state
+		// read back from the connector decides when we are done.
+		resp, err := s.ConnectSubaccount(ctx, regionHost, subaccount)
+		if err != nil && resp != nil && resp.StatusCode >= 400 && resp.StatusCode < 500 && !s.client.retry.statusCodes[resp.StatusCode] {
+			return state, attempts, fmt.Errorf("connecting subaccount %v/%v: %w", regionHost, subaccount, err)
+		}
+		connectErr = err
+	}
+}
+
+// ListSubaccounts lists all subaccounts configured in Cloud Connector
+func (s *SubaccountService) ListSubaccounts(ctx context.Context) ([]*Subaccount, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/subaccounts", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var subaccounts []*Subaccount
+	resp, err := s.client.Do(ctx, req, &subaccounts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return subaccounts, resp, nil
+}
+
+// SubaccountKey identifies a subaccount within Cloud Connector.
+type SubaccountKey struct {
+	RegionHost string
+	Subaccount string
+}
+
+func (k SubaccountKey) String() string {
+	return k.RegionHost + "/" + k.Subaccount
+}
+
+// defaultConcurrency bounds the number of requests issued in parallel by
+// methods operating on many subaccounts.
+const defaultConcurrency = 8
+
+// forEachSubaccount calls fn for every key using at most limit goroutines and
+// waits for all calls to return.
+func forEachSubaccount(keys []SubaccountKey, limit int, fn func(SubaccountKey)) {
+	forEach(len(keys), limit, func(i int) { fn(keys[i]) })
+}
+
+// forEach calls fn with every index in [0, n) using at most limit goroutines,
+// or defaultConcurrency if limit is not positive, and waits for all calls to
+// return.
+func forEach(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = defaultConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// listSubaccountKeys returns the keys of all configured subaccounts.
+func (s *SubaccountService) listSubaccountKeys(ctx context.Context) ([]SubaccountKey, error) {
+	subaccounts, _, err := s.ListSubaccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]SubaccountKey, 0, len(subaccounts))
+	for _, sa := range subaccounts {
+		keys = append(keys, SubaccountKey{RegionHost: sa.RegionHost, Subaccount: sa.Subaccount})
+	}
+	return keys, nil
+}
+
+// UpdateSubaccount updates the display name, description and location ID of a subaccount
+func (s *SubaccountService) UpdateSubaccount(ctx context.Context, regionHost, subaccount string, sa *Subaccount) (*Subaccount, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v", regionHost, subaccount)
+	req, err := s.client.NewRequest("PUT", u, struct {
+		LocationID  string `json:"locationID,omitempty"`
+		DisplayName string `json:"displayName,omitempty"`
+		Description string `json:"description,omitempty"`
+	}{LocationID: sa.LocationID, DisplayName: sa.DisplayName, Description: sa.Description})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(Subaccount)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
+
+// DeleteSubaccount removes a subaccount from Cloud Connector
+func (s *SubaccountService) DeleteSubaccount(ctx context.Context, regionHost, subaccount string) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v", regionHost, subaccount)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ErrDeleteNotConfirmed is returned by DeleteSubaccounts unless the deletion
+// was explicitly confirmed.
+var ErrDeleteNotConfirmed = errors.New("bulk deletion of subaccounts not confirmed")
+
+// DeleteSubaccountsOptions configures DeleteSubaccounts.
+type DeleteSubaccountsOptions struct {
+	// Confirm must be set to true for any subaccount to be deleted. It guards
+	// against accidental mass deletion.
+	Confirm bool
+
+	// Concurrency bounds the number of parallel deletions. Defaults to 8.
+	Concurrency int
+}
+
+// DeleteSubaccounts removes the given subaccounts from Cloud Connector,
+// deleting several concurrently. It continues when individual deletions fail
+// and returns the outcome for each subaccount, a nil error meaning success. If
+// any deletion failed, the error is a *MultiError keyed by subaccount.
+func (s *SubaccountService) DeleteSubaccounts(ctx context.Context, keys []SubaccountKey, opts *DeleteSubaccountsOptions) (map[SubaccountKey]error, error) {
+	if opts == nil || !opts.Confirm {
+		return nil, ErrDeleteNotConfirmed
+	}
+
+	results := make(map[SubaccountKey]error, len(keys))
+	var mu sync.Mutex
+	forEachSubaccount(keys, opts.Concurrency, func(k SubaccountKey) {
+		_, err := s.DeleteSubaccount(ctx, k.RegionHost, k.Subaccount)
+
+		mu.Lock()
+		results[k] = err
+		mu.Unlock()
+	})
+
+	return results, subaccountErrors(results)
+}
+
+// RegionReachability is the result of TestRegionReachability.
+type RegionReachability struct {
+	Reachable bool
+	Latency   time.Duration // time to establish the connection, zero if unreachable
+	Error     string        // e.g. the TLS handshake or proxy error if unreachable
+}
+
+// TestRegionReachability lets Cloud Connector open a test connection to a
+// region host, e.g. to detect firewall or proxy problems before adding a
+// subaccount. An unreachable region is not an error; the reason is reported in
+// the result. It returns ErrNotSupported if the connector has no connectivity
+// test.
+func (s *SubaccountService) TestRegionReachability(ctx context.Context, regionHost string) (*RegionReachability, *Response, error) {
+	if err := ValidateRegionHost(regionHost); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("POST", "api/v1/configuration/connector/connectivityTest", struct {
+		Host string `json:"host"`
+	}{Host: regionHost})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result struct {
+		Reachable       bool   `json:"reachable"`
+		LatencyInMillis int64  `json:"latencyInMillis"`
+		Error           string `json:"error"`
+	}
+	resp, err := s.client.Do(ctx, req, &result)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &RegionReachability{
+		Reachable: result.Reachable,
+		Latency:   time.Duration(result.LatencyInMillis) * time.Millisecond,
+		Error:     result.Error,
+	}, resp, nil
+}
+
+// SubaccountState is the tunnel state of a subaccount.
+type SubaccountState struct {
+	SubaccountKey
+	State string // one of the TunnelState constants
+}
+
+// ListSubaccountStates reads the tunnel state of all subaccounts, reading
+// several subaccounts concurrently. States are returned in the order of
+// ListSubaccounts. Subaccounts whose state cannot be read are left out and
+// reported in a *MultiError keyed by subaccount, returned together with the
+// states that could be read.
+func (s *SubaccountService) ListSubaccountStates(ctx context.Context) ([]*SubaccountState, error) {
+	keys, err := s.listSubaccountKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[SubaccountKey]string, len(keys))
+	results := make(map[SubaccountKey]error, len(keys))
+	var mu sync.Mutex
+	forEachSubaccount(keys, defaultConcurrency, func(k SubaccountKey) {
+		sa, _, err := s.GetSubaccount(ctx, k.RegionHost, k.Subaccount)
+
+		mu.Lock()
+		defer mu.Unlock()
+		results[k] = err
+		if err == nil {
+			state := TunnelStateDisconnected
+			if sa.Tunnel != nil {
+				state = sa.Tunnel.State
+			}
+			states[k] = state
+		}
+	})
+
+	list := make([]*SubaccountState, 0, len(states))
+	for _, k := range keys {
+		if state, ok := states[k]; ok {
+			list = append(list, &SubaccountState{SubaccountKey: k, State: state})
+		}
+	}
+	return list, subaccountErrors(results)
+}
+
+// ListConnectedSubaccounts lists the subaccounts whose tunnel is connected. It
+// reads the states like ListSubaccountStates and returns its error alongside
+// the filtered subaccounts.
+func (s *SubaccountService) ListConnectedSubaccounts(ctx context.Context) ([]SubaccountKey, error) {
+	return s.filterSubaccountStates(ctx, func(state string) bool { return state == TunnelStateConnected })
+}
+
+// ListDisconnectedSubaccounts lists the subaccounts whose tunnel is not
+// connected, including those that failed to connect. It reads the states like
+// ListSubaccountStates and returns its error alongside the filtered
+// subaccounts.
+func (s *SubaccountService) ListDisconnectedSubaccounts(ctx context.Context) ([]SubaccountKey, error) {
+	return s.filterSubaccountStates(ctx, func(state string) bool { return state != TunnelStateConnected })
+}
+
+func (s *SubaccountService) filterSubaccountStates(ctx context.Context, match func(string) bool) ([]SubaccountKey, error) {
+	states, err := s.ListSubaccountStates(ctx)
+	if states == nil {
+		return nil, err
+	}
+
+	keys := []SubaccountKey{}
+	for _, state := range states {
+		if match(state.State) {
+			keys = append(keys, state.SubaccountKey)
+		}
+	}
+	return keys, err
+}
@@ -0,0 +1,47 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubaccountService_AddSubaccount_retryTransportFailure(t *testing.T) {
+	client, mux := setup(t, WithRetry(1, time.Millisecond))
+
+	var posts int32
+	mux.HandleFunc("/api/v1/configuration/subaccounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Request method = %v, want POST", r.Method)
+		}
+		if atomic.AddInt32(&posts, 1) == 1 {
+			// The first attempt creates the subaccount but its response
+			// is lost.
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// The retry fails without any response.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("Hijack returned error: %v", err)
+		}
+		conn.Close()
+	})
+	mux.HandleFunc("/api/v1/configuration/subaccounts/"+testRegionHost+"/"+testSubaccount, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"regionHost":%q,"subaccount":%q,"displayName":"created"}`, testRegionHost, testSubaccount)
+	})
+
+	sa, _, err := client.Subaccount.AddSubaccount(context.Background(), &Subaccount{RegionHost: testRegionHost, Subaccount: testSubaccount, CloudUser: "user", CloudPassword: "secret"})
+	if err != nil {
+		t.Fatalf("AddSubaccount returned error: %v", err)
+	}
+	if sa.DisplayName != "created" {
+		t.Errorf("AddSubaccount returned %+v, want the existing subaccount", sa)
+	}
+	if got := atomic.LoadInt32(&posts); got != 2 {
+		t.Errorf("AddSubaccount sent %d requests, want 2", got)
+	}
+}
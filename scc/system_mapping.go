@@ -0,0 +1,388 @@
+package scc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type SystemMappingService service
+
+// BackendType is the kind of backend system a system mapping points to.
+type BackendType string
+
+// Backend types of system mappings.
+const (
+	BackendTypeABAPSystem            BackendType = "abapSys"
+	BackendTypeNetWeaverCE           BackendType = "netweaverCE"
+	BackendTypeNetWeaverGateway      BackendType = "netweaverGW"
+	BackendTypeApplicationServerJava BackendType = "applServerJava"
+	BackendTypeProcessIntegration    BackendType = "PI"
+	BackendTypeHANA                  BackendType = "hana"
+	BackendTypeOtherSAPSystem        BackendType = "otherSAPsys"
+	BackendTypeNonSAPSystem          BackendType = "nonSAPsys"
+)
+
+var backendTypes = []BackendType{
+	BackendTypeABAPSystem,
+	BackendTypeNetWeaverCE,
+	BackendTypeNetWeaverGateway,
+	BackendTypeApplicationServerJava,
+	BackendTypeProcessIntegration,
+	BackendTypeHANA,
+	BackendTypeOtherSAPSystem,
+	BackendTypeNonSAPSystem,
+}
+
+// BackendTypes returns all backend types accepted by Cloud Connector, e.g. to
+// populate a selection in a user interface.
+func BackendTypes() []BackendType {
+	return append([]BackendType(nil), backendTypes...)
+}
+
+// Valid reports whether t is a backend type accepted by Cloud Connector.
+func (t BackendType) Valid() bool {
+	for _, known := range backendTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SystemMapping maps a virtual host and port exposed to the cloud to an
+// internal backend system.
+type SystemMapping struct {
+	VirtualHost        string      `json:"virtualHost"`
+	VirtualPort        string      `json:"virtualPort"`
+	LocalHost          string      `json:"localHost"`
+	LocalPort          string      `json:"localPort"`
+	Protocol           string      `json:"protocol"`
+	BackendType        BackendType `json:"backendType"`
+	AuthenticationMode string      `json:"authenticationMode,omitempty"`
+	HostInHeader       string      `json:"hostInHeader,omitempty"`
+	SID                string      `json:"sid,omitempty"`
+	SAPRouter          string      `json:"sapRouter,omitempty"`
+	Enabled            *bool       `json:"enabled,omitempty"`
+	Description        string      `json:"description,omitempty"`
+	SNCPartnerName     string      `json:"sncPartnerName,omitempty"`
+	SNCQoP             SNCQoP      `json:"sncQoP,omitempty"`
+
+	// TLS protocol versions allowed towards the backend, e.g. "TLSv1.2",
+	// empty for the connector default. Only used with TLS protocols.
+	TLSProtocols []string `json:"tlsProtocols,omitempty"`
+
+	// Timeouts overriding the connector-wide BackendTimeouts, nil if the
+	// defaults apply.
+	ConnectionTimeoutInSeconds *int `json:"connectionTimeoutInSeconds,omitempty"`
+	ResponseTimeoutInSeconds   *int `json:"responseTimeoutInSeconds,omitempty"`
+}
+
+// SystemMappingResource is a resource (URL path or RFC function name) that
+// is accessible through a system mapping.
+type SystemMappingResource struct {
+	ID                      string `json:"id"`
+	Enabled                 bool   `json:"enabled"`
+	ExactMatchOnly          bool   `json:"exactMatchOnly"`
+	WebsocketUpgradeAllowed bool   `json:"websocketUpgradeAllowed,omitempty"`
+	Description             string `json:"description,omitempty"`
+}
+
+// ListSystemMappings lists the system mappings of a subaccount
+func (s *SystemMappingService) ListSystemMappings(ctx context.Context, regionHost, subaccount string) ([]*SystemMapping, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings", regionHost, subaccount)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappings []*SystemMapping
+	resp, err := s.client.Do(ctx, req, &mappings)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return mappings, resp, nil
+}
+
+// GetSystemMapping gets a system mapping of a subaccount
+func (s *SystemMappingService) GetSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SystemMapping, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v", regionHost, subaccount, virtualHost, virtualPort)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mapping := new(SystemMapping)
+	resp, err := s.client.Do(ctx, req, mapping)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return mapping, resp, nil
+}
+
+// SetMappingDescription sets the description of a system mapping, leaving its
+// other settings unchanged.
+func (s *SystemMappingService) SetMappingDescription(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, description string) (*Response, error) {
+	mapping, resp, err := s.GetSystemMapping(ctx, regionHost, subaccount, virtualHost, virtualPort)
+	if err != nil {
+		return resp, err
+	}
+
+	mapping.Description = description
+	return s.UpdateSystemMapping(ctx, regionHost, subaccount, mapping)
+}
+
+// GetEffectiveSystemMapping gets a system mapping of a subaccount with the
+// settings it inherits from the connector filled in, i.e. the values that
+// apply at runtime. The connector defaults are read once and cached by the
+// client.
+func (s *SystemMappingService) GetEffectiveSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*SystemMapping, *Response, error) {
+	mapping, resp, err := s.GetSystemMapping(ctx, regionHost, subaccount, virtualHost, virtualPort)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	defaults, err := s.client.backendTimeouts(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if mapping.ConnectionTimeoutInSeconds == nil {
+		mapping.ConnectionTimeoutInSeconds = Int(defaults.ConnectionTimeoutInSeconds)
+	}
+	if mapping.ResponseTimeoutInSeconds == nil {
+		mapping.ResponseTimeoutInSeconds = Int(defaults.ResponseTimeoutInSeconds)
+	}
+	return mapping, resp, nil
+}
+
+// SetAllMappingsEnabled enables or disables all system mappings of a
+// subaccount, updating several mappings concurrently. It continues when
+// individual updates fail and returns the outcome for each mapping, keyed by
+// "virtualHost:virtualPort", a nil error meaning success. If any update
+// failed, the error is a *MultiError with the same keys.
+func (s *SystemMappingService) SetAllMappingsEnabled(ctx context.Context, regionHost, subaccount string, enabled bool) (map[string]error, error) {
+	mappings, _, err := s.ListSystemMappings(ctx, regionHost, subaccount)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]error, len(mappings))
+	var mu sync.Mutex
+	forEach(len(mappings), defaultConcurrency, func(i int) {
+		m := mappings[i]
+		m.Enabled = Bool(enabled)
+		_, err := s.UpdateSystemMapping(ctx, regionHost, subaccount, m)
+
+		mu.Lock()
+		results[m.VirtualHost+":"+m.VirtualPort] = err
+		mu.Unlock()
+	})
+
+	return results, itemErrors(results)
+}
+
+// MappingTLSInfo describes how a system mapping secures its connection to
+// the backend.
+type MappingTLSInfo struct {
+	Encrypted                 bool     // the backend protocol uses TLS
+	ClientCertificateRequired bool     // the connector authenticates with an X.509 certificate
+	Protocols                 []string // allowed TLS protocol versions, empty for the default
+}
+
+// GetMappingTLSInfo gets the TLS settings of a system mapping, e.g. to find
+// mappings that talk to their backend in plain text.
+func (s *SystemMappingService) GetMappingTLSInfo(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*MappingTLSInfo, *Response, error) {
+	mapping, resp, err := s.GetSystemMapping(ctx, regionHost, subaccount, virtualHost, virtualPort)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	info := &MappingTLSInfo{ClientCertificateRequired: strings.HasPrefix(mapping.AuthenticationMode, "X509")}
+	switch strings.ToUpper(mapping.Protocol) {
+	case "HTTPS", "RFCS", "LDAPS", "TCPSSL":
+		info.Encrypted = true
+		info.Protocols = mapping.TLSProtocols
+	}
+	return info, resp, nil
+}
+
+// SystemMappingExists reports whether a subaccount has a system mapping for the given virtual host and port
+func (s *SystemMappingService) SystemMappingExists(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (bool, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return false, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v", regionHost, subaccount, virtualHost, virtualPort)
+	return s.client.exists(ctx, u)
+}
+
+// CreateSystemMapping creates a system mapping for a subaccount
+func (s *SystemMappingService) CreateSystemMapping(ctx context.Context, regionHost, subaccount string, mapping *SystemMapping) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	if !mapping.BackendType.Valid() {
+		return nil, fmt.Errorf("invalid backend type %q", mapping.BackendType)
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings", regionHost, subaccount)
+	req, err := s.client.NewRequest("POST", u, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// UpdateSystemMapping updates the system mapping of a subaccount identified by
+// the virtual host and port of mapping
+func (s *SystemMappingService) UpdateSystemMapping(ctx context.Context, regionHost, subaccount string, mapping *SystemMapping) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	if mapping.BackendType != "" && !mapping.BackendType.Valid() {
+		return nil, fmt.Errorf("invalid backend type %q", mapping.BackendType)
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v", regionHost, subaccount, mapping.VirtualHost, mapping.VirtualPort)
+	req, err := s.client.NewRequest("PUT", u, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// DeleteSystemMapping deletes a system mapping of a subaccount
+func (s *SystemMappingService) DeleteSystemMapping(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v", regionHost, subaccount, virtualHost, virtualPort)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ListSystemMappingResources lists the resources accessible through a system mapping
+func (s *SystemMappingService) ListSystemMappingResources(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string) ([]*SystemMappingResource, *Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources", regionHost, subaccount, virtualHost, virtualPort)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resources []*SystemMappingResource
+	resp, err := s.client.Do(ctx, req, &resources)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resources, resp, nil
+}
+
+// CreateSystemMappingResource makes a resource accessible through a system mapping
+func (s *SystemMappingService) CreateSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, resource *SystemMappingResource) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources", regionHost, subaccount, virtualHost, virtualPort)
+	req, err := s.client.NewRequest("POST", u, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// UpdateSystemMappingResource updates a resource of a system mapping
+// identified by the ID of resource
+func (s *SystemMappingService) UpdateSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort string, resource *SystemMappingResource) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources/%v", regionHost, subaccount, virtualHost, virtualPort, encodeResourceID(resource.ID))
+	req, err := s.client.NewRequest("PUT", u, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// DeleteSystemMappingResource deletes a resource of a system mapping
+func (s *SystemMappingService) DeleteSystemMappingResource(ctx context.Context, regionHost, subaccount, virtualHost, virtualPort, resourceID string) (*Response, error) {
+	if err := s.client.resolveSubaccountKey(&regionHost, subaccount); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("api/v1/configuration/subaccounts/%v/%v/systemMappings/%v:%v/resources/%v", regionHost, subaccount, virtualHost, virtualPort, encodeResourceID(resourceID))
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// encodeResourceID encodes a resource ID, typically a URL path, for use as a
+// path segment. Cloud Connector expects resource IDs to be Base64 encoded.
+func encodeResourceID(id string) string {
+	return base64.URLEncoding.EncodeToString([]byte(id))
+}
+
+// ExposedHost is a virtual host and port a subaccount exposes to the cloud.
+type ExposedHost struct {
+	VirtualHost string
+	VirtualPort string
+	Protocol    string
+	BackendType BackendType
+	Enabled     bool
+}
+
+// ListExposedHosts lists the virtual hosts and ports exposed by the system
+// mappings of a subaccount. A mapping that does not report an enabled state
+// is considered enabled.
+func (s *SystemMappingService) ListExposedHosts(ctx context.Context, regionHost, subaccount string) ([]*ExposedHost, *Response, error) {
+	mappings, resp, err := s.ListSystemMappings(ctx, regionHost, subaccount)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	hosts := make([]*ExposedHost, 0, len(mappings))
+	for _, m := range mappings {
+		hosts = append(hosts, &ExposedHost{
+			VirtualHost: m.VirtualHost,
+			VirtualPort: m.VirtualPort,
+			Protocol:    m.Protocol,
+			BackendType: m.BackendType,
+			Enabled:     m.Enabled == nil || *m.Enabled,
+		})
+	}
+	return hosts, resp, nil
+}
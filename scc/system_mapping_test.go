@@ -0,0 +1,72 @@
+package scc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const (
+	testRegionHost = "cf.eu10.hana.ondemand.com"
+	testSubaccount = "0c2c1b4a-7e0f-4a5e-9c3d-2b1a0f9e8d7c"
+)
+
+func TestSystemMappingService_descriptionRoundTrip(t *testing.T) {
+	client, mux := setup(t)
+
+	var mu sync.Mutex
+	stored := []byte(`{"virtualHost":"virtual","virtualPort":"443","localHost":"local","localPort":"8443","protocol":"HTTPS","backendType":"abapSys","authenticationMode":"NONE_RESTRICTED","hostInHeader":"VIRTUAL","enabled":true,"description":"ERP backend","tlsProtocols":["TLSv1.2"],"connectionTimeoutInSeconds":30,"responseTimeoutInSeconds":60}`)
+	mux.HandleFunc("/api/v1/configuration/subaccounts/"+testRegionHost+"/"+testSubaccount+"/systemMappings/virtual:443", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "GET":
+			w.Write(stored)
+		case "PUT":
+			var raw json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+			stored = raw
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request method %v", r.Method)
+		}
+	})
+	ctx := context.Background()
+	get := func() *SystemMapping {
+		t.Helper()
+		mapping, _, err := client.SystemMapping.GetSystemMapping(ctx, testRegionHost, testSubaccount, "virtual", "443")
+		if err != nil {
+			t.Fatalf("GetSystemMapping returned error: %v", err)
+		}
+		return mapping
+	}
+
+	original := get()
+	if original.Description != "ERP backend" {
+		t.Fatalf("GetSystemMapping returned description %q, want %q", original.Description, "ERP backend")
+	}
+
+	if _, err := client.SystemMapping.SetMappingDescription(ctx, testRegionHost, testSubaccount, "virtual", "443", "ERP production"); err != nil {
+		t.Fatalf("SetMappingDescription returned error: %v", err)
+	}
+	want := *original
+	want.Description = "ERP production"
+	described := get()
+	if !reflect.DeepEqual(described, &want) {
+		t.Errorf("after SetMappingDescription got %+v, want %+v", described, &want)
+	}
+
+	described.LocalPort = "9443"
+	if _, err := client.SystemMapping.UpdateSystemMapping(ctx, testRegionHost, testSubaccount, described); err != nil {
+		t.Fatalf("UpdateSystemMapping returned error: %v", err)
+	}
+	want.LocalPort = "9443"
+	if updated := get(); !reflect.DeepEqual(updated, &want) {
+		t.Errorf("after UpdateSystemMapping got %+v, want %+v", updated, &want)
+	}
+}
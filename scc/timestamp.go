@@ -0,0 +1,56 @@
+package scc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// timestampLayouts are the string formats in which Cloud Connector reports
+// points in time, tried in order.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000 -0700",
+	"2006-01-02 15:04:05 -0700",
+}
+
+// Timestamp represents a time that can be unmarshalled from a JSON string in
+// one of the formats used by Cloud Connector, or from a number of milliseconds
+// since the Unix epoch. It marshals to RFC 3339.
+type Timestamp struct {
+	time.Time
+}
+
+func (t Timestamp) String() string {
+	return t.Time.String()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if ms, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		t.Time = time.Unix(0, ms*int64(time.Millisecond)).UTC()
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	var err error
+	for _, layout := range timestampLayouts {
+		var parsed time.Time
+		if parsed, err = time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package scc
+
+import (
+	"context"
+	"errors"
+)
+
+// TLSPolicy lists the TLS protocol versions and cipher suites Cloud Connector
+// accepts on its administration and tunnel ports.
+type TLSPolicy struct {
+	Protocols    []string `json:"protocols"`    // e.g. "TLSv1.2", "TLSv1.3"
+	CipherSuites []string `json:"cipherSuites"` // e.g. "TLS_AES_128_GCM_SHA256"
+}
+
+// GetTLSPolicy gets the TLS protocols and cipher suites enabled in Cloud Connector
+func (s *CommonService) GetTLSPolicy(ctx context.Context) (*TLSPolicy, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/tls", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy := new(TLSPolicy)
+	resp, err := s.client.Do(ctx, req, policy)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return policy, resp, nil
+}
+
+// SetTLSPolicy sets the TLS protocols and cipher suites enabled in Cloud
+// Connector. At least one protocol must remain enabled, as the administration
+// interface would otherwise become unreachable.
+func (s *CommonService) SetTLSPolicy(ctx context.Context, policy *TLSPolicy) (*TLSPolicy, *Response, error) {
+	if len(policy.Protocols) == 0 {
+		return nil, nil, errors.New("at least one TLS protocol must be enabled")
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/tls", policy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(TLSPolicy)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
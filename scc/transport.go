@@ -0,0 +1,190 @@
+package scc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// dialConfig holds the settings used to establish connections to Cloud
+// Connector, configured with WithKeepAlive, WithDialTimeout, WithDNSCache and
+// WithDialContext.
+type dialConfig struct {
+	dialer      net.Dialer
+	dnsTTL      time.Duration
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func newDialConfig() dialConfig {
+	// Same values as http.DefaultTransport.
+	return dialConfig{dialer: net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}}
+}
+
+// WithKeepAlive sets the interval between TCP keep-alive probes on connections
+// to Cloud Connector. A negative value disables keep-alives.
+//
+// Like the other dial options, it clones the transport of the HTTP client and
+// leaves the http.Client passed to NewClient untouched. It requires that
+// client's transport to be nil, an *http.Transport or a BasicAuthTransport
+// wrapping one of those.
+func WithKeepAlive(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.dial.dialer.KeepAlive = d
+		return c.applyDialConfig()
+	}
+}
+
+// WithDialTimeout sets the maximum time to wait for a connection to Cloud
+// Connector to be established. See WithKeepAlive for requirements on the HTTP
+// client.
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.dial.dialer.Timeout = d
+		return c.applyDialConfig()
+	}
+}
+
+// WithDNSCache caches the addresses the Cloud Connector host resolves to for
+// ttl, avoiding a DNS lookup for every new connection when polling at high
+// frequency. See WithKeepAlive for requirements on the HTTP client.
+func WithDNSCache(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.dial.dnsTTL = ttl
+		return c.applyDialConfig()
+	}
+}
+
+// WithDialContext makes the client establish connections to Cloud Connector
+// with dial, e.g. to reach it through a SOCKS5 proxy or an SSH tunnel. See
+// WithKeepAlive for requirements on the HTTP client.
+//
+// dial replaces the dialer configured with WithKeepAlive and WithDialTimeout.
+// A proxy configured in the transport's Proxy field is still used; dial is
+// then called to connect to the proxy rather than to Cloud Connector. Combined
+// with WithDNSCache, host names are resolved locally before dial is called,
+// which defeats proxies that resolve names remotely.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(c *Client) error {
+		if dial == nil {
+			return errors.New("dial function must not be nil")
+		}
+		c.dial.dialContext = dial
+		return c.applyDialConfig()
+	}
+}
+
+func (c *Client) applyDialConfig() error {
+	dialer := c.dial.dialer
+	dial := dialer.DialContext
+	if c.dial.dialContext != nil {
+		dial = c.dial.dialContext
+	}
+	if c.dial.dnsTTL > 0 {
+		dial = (&dnsCache{ttl: c.dial.dnsTTL, dial: dial}).DialContext
+	}
+	return c.configureTransport(func(t *http.Transport) {
+		t.DialContext = dial
+	})
+}
+
+// configureTransport applies configure to a clone of the *http.Transport used
+// by c's HTTP client and installs the result on a copy of the HTTP client.
+func (c *Client) configureTransport(configure func(*http.Transport)) error {
+	c.clientMu.Lock()
+	defer c.clientMu.Unlock()
+
+	transport, err := cloneTransport(c.client.Transport, configure)
+	if err != nil {
+		return err
+	}
+	clientCopy := *c.client
+	clientCopy.Transport = transport
+	c.client = &clientCopy
+	return nil
+}
+
+func cloneTransport(rt http.RoundTripper, configure func(*http.Transport)) (http.RoundTripper, error) {
+	switch t := rt.(type) {
+	case nil:
+		clone := http.DefaultTransport.(*http.Transport).Clone()
+		configure(clone)
+		return clone, nil
+	case *http.Transport:
+		clone := t.Clone()
+		configure(clone)
+		return clone, nil
+	case *BasicAuthTransport:
+		inner, err := cloneTransport(t.Transport, configure)
+		if err != nil {
+			return nil, err
+		}
+		username, password := t.credentials()
+		return &BasicAuthTransport{Username: username, Password: password, Transport: inner}, nil
+	case transportWrapper:
+		inner, err := cloneTransport(t.unwrap(), configure)
+		if err != nil {
+			return nil, err
+		}
+		return t.rewrap(inner), nil
+	}
+	return nil, fmt.Errorf("cannot configure HTTP transport of type %T", rt)
+}
+
+// dnsCache resolves hosts before dialing and remembers the results for ttl.
+type dnsCache struct {
+	ttl  time.Duration
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+func (d *dnsCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.dial(ctx, network, addr)
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		var conn net.Conn
+		if conn, err = d.dial(ctx, network, net.JoinHostPort(a, port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func (d *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	if d.entries == nil {
+		d.entries = make(map[string]dnsCacheEntry)
+	}
+	d.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(d.ttl)}
+	d.mu.Unlock()
+	return addrs, nil
+}
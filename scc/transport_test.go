@@ -0,0 +1,40 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithKeepAlive_afterReplayFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"2.15.0"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "replay.json")
+	client, err := NewClient(server.URL+"/", nil, WithReplayFile(path), WithKeepAlive(time.Second))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	replay, ok := client.Client().Transport.(*ReplayTransport)
+	if !ok {
+		t.Fatalf("transport is %T, want *ReplayTransport", client.Client().Transport)
+	}
+	if _, ok := replay.Transport.(*http.Transport); !ok {
+		t.Errorf("replayed transport wraps %T, want *http.Transport", replay.Transport)
+	}
+
+	version, _, err := client.Common.GetVersion(context.Background())
+	if err != nil {
+		t.Fatalf("GetVersion returned error: %v", err)
+	}
+	if version.Version != "2.15.0" {
+		t.Errorf("GetVersion returned %q, want %q", version.Version, "2.15.0")
+	}
+}
@@ -0,0 +1,65 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Compression algorithms for tunnel traffic.
+const (
+	CompressionAlgorithmGzip    = "gzip"
+	CompressionAlgorithmDeflate = "deflate"
+)
+
+// TunnelCompression is the compression setting of the tunnels to SAP BTP.
+type TunnelCompression struct {
+	Enabled   bool   `json:"enabled"`
+	Algorithm string `json:"algorithm,omitempty"` // empty for the connector's default
+}
+
+// GetTunnelCompression gets the compression setting of the tunnels. It
+// returns ErrNotSupported if the connector does not support compression.
+func (s *CommonService) GetTunnelCompression(ctx context.Context) (*TunnelCompression, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/tunnel/compression", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	compression := new(TunnelCompression)
+	resp, err := s.client.Do(ctx, req, compression)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return compression, resp, nil
+}
+
+// SetTunnelCompression sets the compression setting of the tunnels and
+// returns the effective setting. It returns ErrNotSupported if the connector
+// does not support compression.
+func (s *CommonService) SetTunnelCompression(ctx context.Context, compression *TunnelCompression) (*TunnelCompression, *Response, error) {
+	switch compression.Algorithm {
+	case "", CompressionAlgorithmGzip, CompressionAlgorithmDeflate:
+	default:
+		return nil, nil, fmt.Errorf("invalid compression algorithm %q", compression.Algorithm)
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/tunnel/compression", compression)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(TunnelCompression)
+	resp, err := s.client.Do(ctx, req, updated)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
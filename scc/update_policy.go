@@ -0,0 +1,57 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Update policies of Cloud Connector.
+const (
+	UpdatePolicyAutomatic = "automatic"
+	UpdatePolicyManual    = "manual"
+)
+
+// UpdatePolicy controls how Cloud Connector is updated.
+type UpdatePolicy struct {
+	Policy  string `json:"policy"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// GetUpdatePolicy gets the update policy of Cloud Connector
+func (s *CommonService) GetUpdatePolicy(ctx context.Context) (*UpdatePolicy, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/update", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy := new(UpdatePolicy)
+	resp, err := s.client.Do(ctx, req, policy)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return policy, resp, nil
+}
+
+// SetUpdatePolicy sets the update policy of Cloud Connector. The policy must
+// be UpdatePolicyAutomatic or UpdatePolicyManual.
+func (s *CommonService) SetUpdatePolicy(ctx context.Context, policy *UpdatePolicy) (*UpdatePolicy, *Response, error) {
+	switch policy.Policy {
+	case UpdatePolicyAutomatic, UpdatePolicyManual:
+	default:
+		return nil, nil, fmt.Errorf("invalid update policy %q", policy.Policy)
+	}
+
+	req, err := s.client.NewRequest("PUT", "api/v1/configuration/connector/update", policy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated := new(UpdatePolicy)
+	resp, err := s.client.Do(ctx, req, updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
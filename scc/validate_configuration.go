@@ -0,0 +1,49 @@
+package scc
+
+import (
+	"context"
+)
+
+// Severity is the severity of a ConfigurationFinding.
+type Severity string
+
+// Severities of configuration findings.
+const (
+	SeverityInfo    Severity = "INFO"
+	SeverityWarning Severity = "WARNING"
+	SeverityError   Severity = "ERROR"
+)
+
+// ConfigurationFinding is a problem found by ValidateConfiguration.
+type ConfigurationFinding struct {
+	Severity   Severity `json:"severity"`
+	Message    string   `json:"message"`
+	RegionHost string   `json:"regionHost,omitempty"` // set for findings of a subaccount
+	Subaccount string   `json:"subaccount,omitempty"`
+	Object     string   `json:"object,omitempty"` // e.g. the affected system mapping
+}
+
+// ValidateConfiguration lets Cloud Connector check its current configuration
+// for problems such as unreachable backends, expired certificates or invalid
+// mappings. A clean configuration yields an empty slice. It returns
+// ErrNotSupported if the connector has no validation endpoint.
+func (s *CommonService) ValidateConfiguration(ctx context.Context) ([]*ConfigurationFinding, *Response, error) {
+	req, err := s.client.NewRequest("GET", "api/v1/configuration/connector/validation", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var findings []*ConfigurationFinding
+	resp, err := s.client.Do(ctx, req, &findings)
+	if isNotFound(err) {
+		return nil, resp, ErrNotSupported
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if findings == nil {
+		findings = []*ConfigurationFinding{}
+	}
+	return findings, resp, nil
+}
@@ -0,0 +1,52 @@
+package scc
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// regionHostRE matches host names such as cf.eu10.hana.ondemand.com.
+	regionHostRE = regexp.MustCompile(`^(?i)([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+	// subaccountIDRE matches subaccount GUIDs as well as the technical
+	// names of Neo subaccounts.
+	subaccountIDRE = regexp.MustCompile(`^(?i)([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[a-z0-9]+)$`)
+)
+
+// ValidateRegionHost checks that regionHost is a well-formed host name, such
+// as cf.eu10.hana.ondemand.com.
+func ValidateRegionHost(regionHost string) error {
+	if !regionHostRE.MatchString(regionHost) {
+		return fmt.Errorf("invalid region host %q", regionHost)
+	}
+	return nil
+}
+
+// ValidateSubaccountID checks that subaccount is a well-formed subaccount ID,
+// i.e. a GUID or the alphanumeric technical name of a Neo subaccount.
+func ValidateSubaccountID(subaccount string) error {
+	if !subaccountIDRE.MatchString(subaccount) {
+		return fmt.Errorf("invalid subaccount ID %q", subaccount)
+	}
+	return nil
+}
+
+// validateSubaccountKey validates the region host and subaccount ID that
+// identify a subaccount in request paths.
+func validateSubaccountKey(regionHost, subaccount string) error {
+	if err := ValidateRegionHost(regionHost); err != nil {
+		return err
+	}
+	return ValidateSubaccountID(subaccount)
+}
+
+// resolveSubaccountKey validates a subaccount key like validateSubaccountKey,
+// first replacing an empty region host with the default configured with
+// WithDefaultRegionHost.
+func (c *Client) resolveSubaccountKey(regionHost *string, subaccount string) error {
+	if *regionHost == "" {
+		*regionHost = c.defaultRegionHost
+	}
+	return validateSubaccountKey(*regionHost, subaccount)
+}
@@ -0,0 +1,55 @@
+package scc
+
+import "testing"
+
+func TestValidateRegionHost(t *testing.T) {
+	tests := []struct {
+		regionHost string
+		valid      bool
+	}{
+		{"cf.eu10.hana.ondemand.com", true},
+		{"hana.ondemand.com", true},
+		{"CF.US10.HANA.ONDEMAND.COM", true},
+		{"cf-eu10.example.io", true},
+		{"", false},
+		{"localhost", false},
+		{"..", false},
+		{"a/b", false},
+		{"cf.eu10.hana.ondemand.com/../x", false},
+		{"-cf.eu10.hana.ondemand.com", false},
+		{"cf..hana.ondemand.com", false},
+		{"cf.eu10.hana.ondemand.com:443", false},
+		{"cf eu10.hana.ondemand.com", false},
+	}
+	for _, tt := range tests {
+		err := ValidateRegionHost(tt.regionHost)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateRegionHost(%q) = %v, want valid %v", tt.regionHost, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateSubaccountID(t *testing.T) {
+	tests := []struct {
+		subaccount string
+		valid      bool
+	}{
+		{"0c2c1b4a-7e0f-4a5e-9c3d-2b1a0f9e8d7c", true},
+		{"0C2C1B4A-7E0F-4A5E-9C3D-2B1A0F9E8D7C", true},
+		{"a1b2c3d4", true},
+		{"", false},
+		{"..", false},
+		{"a/b", false},
+		{"0c2c1b4a-7e0f-4a5e-9c3d", false},
+		{"0c2c1b4a-7e0f-4a5e-9c3d-2b1a0f9e8d7c/..", false},
+		{"0g2c1b4a-7e0f-4a5e-9c3d-2b1a0f9e8d7c", false},
+		{"sub account", false},
+		{"sub%2Faccount", false},
+	}
+	for _, tt := range tests {
+		err := ValidateSubaccountID(tt.subaccount)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateSubaccountID(%q) = %v, want valid %v", tt.subaccount, err, tt.valid)
+		}
+	}
+}
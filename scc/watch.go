@@ -0,0 +1,62 @@
+package scc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// WatchFunc fetches the value observed by Watch, typically by calling one of
+// the Get or List methods of a service.
+type WatchFunc func(ctx context.Context) (interface{}, *Response, error)
+
+// Watch calls fetch every interval and calls onChange with the result
+// whenever it differs, by deep equality, from the previous successful result.
+// The first successful result is always reported. Fetch errors are passed to
+// onError, if not nil, and do not stop watching. Watch blocks until ctx is done
+// and then returns ctx.Err(). interval must be positive.
+//
+// For example, to watch the tunnel state of a subaccount:
+//
+//	scc.Watch(ctx, func(ctx context.Context) (interface{}, *scc.Response, error) {
+//		sa, resp, err := client.Subaccount.GetSubaccount(ctx, regionHost, subaccount)
+//		if err != nil {
+//			return nil, resp, err
+//		}
+//		return sa.Tunnel.State, resp, nil
+//	}, 10*time.Second, func(v interface{}) {
+//		log.Printf("tunnel state: %v", v)
+//	}, nil)
+func Watch(ctx context.Context, fetch WatchFunc, interval time.Duration, onChange func(interface{}), onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid watch interval %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last interface{}
+	seen := false
+	for {
+		v, _, err := fetch(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if onError != nil {
+				onError(err)
+			}
+		case !seen || !reflect.DeepEqual(last, v):
+			last, seen = v, true
+			onChange(v)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}